@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/binding"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"strconv"
 	"time"
 )
 
@@ -39,6 +41,32 @@ func Start(ctx context.Context, port int, bs *binding.Service) (*Server, error)
 	s.echoWebServer.GET("/bindings/stats", func(c echo.Context) error {
 		return c.JSONPretty(200, s.bindingService.Stats(), "\t")
 	})
+	s.echoWebServer.GET("/connections", func(c echo.Context) error {
+		return c.JSONPretty(200, connpool.Status(c.Request().Context()), "\t")
+	})
+	s.echoWebServer.GET("/bindings/:name/checkpoints", func(c echo.Context) error {
+		list, err := s.bindingService.Checkpoints(c.Param("name"))
+		if err != nil {
+			return c.String(404, err.Error())
+		}
+		return c.JSONPretty(200, list, "\t")
+	})
+	s.echoWebServer.POST("/bindings/:name/checkpoints/reset", func(c echo.Context) error {
+		if err := s.bindingService.RewindCheckpoints(c.Param("name"), 0); err != nil {
+			return c.String(400, err.Error())
+		}
+		return c.String(200, "ok")
+	})
+	s.echoWebServer.POST("/bindings/:name/checkpoints/rewind", func(c echo.Context) error {
+		sequence, err := strconv.ParseUint(c.QueryParam("sequence"), 10, 64)
+		if err != nil || sequence == 0 {
+			return c.String(400, "invalid sequence value")
+		}
+		if err := s.bindingService.RewindCheckpoints(c.Param("name"), sequence); err != nil {
+			return c.String(400, err.Error())
+		}
+		return c.String(200, "ok")
+	})
 	errCh := make(chan error, 1)
 	go func() {
 		errCh <- s.echoWebServer.Start(fmt.Sprintf("0.0.0.0:%d", port))
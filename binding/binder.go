@@ -5,10 +5,21 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/kubemq-io/kubemq-bridges/pkg/checkpoint"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
 	"github.com/kubemq-io/kubemq-bridges/sources"
 	"github.com/kubemq-io/kubemq-bridges/targets"
+	"math"
+)
+
+const (
+	defaultShadowConcurrency = 10
+	defaultShadowQueueSize   = 1000
 )
 
 type Binder struct {
@@ -17,12 +28,20 @@ type Binder struct {
 	sources           []sources.Source
 	targetsMiddleware []middleware.Middleware
 	targets           []targets.Target
+	exporter          *metrics.Exporter
+	pipeline          *middleware.Pipeline
+	shadowPool        *pool.Pool
+	audit             *middleware.AuditMiddleware
+	gapCheck          *middleware.GapCheckMiddleware
 }
 
 func NewBinder() *Binder {
 	return &Binder{}
 }
-func (b *Binder) buildMiddleware(target targets.Target, cfg config.BindingConfig, exporter *metrics.Exporter, log *middleware.LogMiddleware) (middleware.Middleware, error) {
+func (b *Binder) buildMiddleware(index int, target targets.Target, cfg config.BindingConfig, exporter *metrics.Exporter, log *middleware.LogMiddleware) (middleware.Middleware, error) {
+	if b.pipeline != nil {
+		return b.pipeline.Build(target, index)
+	}
 
 	retry, err := middleware.NewRetryMiddleware(cfg.Properties, b.log)
 	if err != nil {
@@ -32,38 +51,127 @@ func (b *Binder) buildMiddleware(target targets.Target, cfg config.BindingConfig
 	if err != nil {
 		return nil, err
 	}
+	address := cfg.Targets.Connections[index].ParseString("address", "")
+	var next middleware.Middleware = target
+	if b.audit != nil {
+		next = middleware.Chain(target, middleware.Audit(b.audit, index, address))
+	}
 	var md middleware.Middleware
 	if exporter != nil {
 		met, err := middleware.NewMetricsMiddleware(cfg, exporter)
 		if err != nil {
 			return nil, err
 		}
-		md = middleware.Chain(target, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Metric(met), middleware.Log(log))
+		md = middleware.Chain(next, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Metric(met), middleware.Log(log))
 	} else {
-		md = middleware.Chain(target, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Log(log))
+		md = middleware.Chain(next, middleware.RateLimiter(rateLimiter), middleware.Retry(retry), middleware.Log(log))
+	}
+	loopPrevention := cfg.Properties.ParseBool("loop_prevention", false)
+	if cfg.Properties.ParseBool("provenance", false) || loopPrevention {
+		pm, err := middleware.NewProvenanceMiddleware(cfg.Name, address, cfg.Properties)
+		if err != nil {
+			return nil, err
+		}
+		if loopPrevention {
+			pm.Require(middleware.LoopProvenanceFields...)
+		}
+		md = middleware.Chain(md, middleware.Provenance(pm))
+	}
+	if loopPrevention {
+		lm, err := middleware.NewLoopMiddleware(cfg, address, cfg.Properties, exporter, b.log)
+		if err != nil {
+			return nil, err
+		}
+		md = middleware.Chain(md, middleware.Loop(lm))
 	}
-
 	return md, nil
 }
+
+// attachShadows mirrors requests to the shadow targets from the primary targets. Fan-out sources
+// send each request to every primary target, so only the first one mirrors it; otherwise each
+// request reaches a single primary target, and all of them mirror it.
+func (b *Binder) attachShadows(cfg config.BindingConfig, shadows []middleware.Middleware) error {
+	concurrency, err := cfg.Properties.ParseIntWithRange("shadow_concurrency", defaultShadowConcurrency, 1, 10000)
+	if err != nil {
+		return fmt.Errorf("invalid shadow concurrency value, %w", err)
+	}
+	queueSize, err := cfg.Properties.ParseIntWithRange("shadow_queue_size", defaultShadowQueueSize, 0, math.MaxInt32)
+	if err != nil {
+		return fmt.Errorf("invalid shadow queue size value, %w", err)
+	}
+	b.shadowPool = pool.New("shadow", concurrency, queueSize)
+	shadow := middleware.Shadow(shadows, b.shadowPool, b.log)
+	fanOut := cfg.Properties.ParseString("load-balancing", "") != "true"
+	switch cfg.Sources.Kind {
+	case "source.command", "kubemq.command", "source.query", "kubemq.query":
+		fanOut = cfg.Properties.ParseString("response_strategy", "") != ""
+	}
+	for i, md := range b.targetsMiddleware {
+		if fanOut && i > 0 {
+			break
+		}
+		b.targetsMiddleware[i] = middleware.Chain(md, shadow)
+	}
+	return nil
+}
+
 func (b *Binder) Init(ctx context.Context, cfg config.BindingConfig, exporter *metrics.Exporter) error {
 	b.name = cfg.Name
+	b.exporter = exporter
 	log, err := middleware.NewLogMiddleware(cfg.Name, cfg.Properties)
 	if err != nil {
 		return err
 	}
 	b.log = log.Logger
-	for _, connection := range cfg.Targets.Connections {
+	if len(cfg.Middlewares) > 0 {
+		b.pipeline, err = middleware.NewPipeline(cfg, exporter, b.log)
+		if err != nil {
+			return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
+		}
+	} else if cfg.Properties.ParseString("audit_file", "") != "" {
+		b.audit, err = middleware.NewAuditMiddleware(cfg.Name, cfg.Properties, b.log)
+		if err != nil {
+			return fmt.Errorf("error loading audit on binding %s, %w", b.name, err)
+		}
+	}
+	var shadows []middleware.Middleware
+	for i, connection := range cfg.Targets.Connections {
 		target, err := targets.Init(ctx, cfg.Targets.Kind, connection, b.log)
 		if err != nil {
 			return fmt.Errorf("error loading targets conntector on binding %s, %w", b.name, err)
 		}
-		md, err := b.buildMiddleware(target, cfg, exporter, log)
+		md, err := b.buildMiddleware(i, target, cfg, exporter, log)
 		if err != nil {
 			return fmt.Errorf("error loading middlewares on binding %s, %w", b.name, err)
 		}
-		b.targetsMiddleware = append(b.targetsMiddleware, md)
+		sampling, err := middleware.NewSamplingMiddleware(connection)
+		if err != nil {
+			return fmt.Errorf("error loading sampling on binding %s, %w", b.name, err)
+		}
+		if sampling.IsActive() {
+			md = middleware.Chain(md, middleware.Sampling(sampling))
+		}
+		if connection.ParseBool("shadow", false) {
+			shadows = append(shadows, md)
+		} else {
+			b.targetsMiddleware = append(b.targetsMiddleware, md)
+		}
 		b.targets = append(b.targets, target)
 	}
+	if len(shadows) > 0 {
+		if err := b.attachShadows(cfg, shadows); err != nil {
+			return fmt.Errorf("error loading shadow targets on binding %s, %w", b.name, err)
+		}
+	}
+	if cfg.Properties.ParseBool("timer_gap_check", false) {
+		b.gapCheck, err = middleware.NewGapCheckMiddleware(cfg.Properties, b.log)
+		if err != nil {
+			return fmt.Errorf("error loading timer gap check on binding %s, %w", b.name, err)
+		}
+		for i, md := range b.targetsMiddleware {
+			b.targetsMiddleware[i] = middleware.Chain(md, middleware.GapCheck(b.gapCheck))
+		}
+	}
 
 	for _, connection := range cfg.Sources.Connections {
 		source, err := sources.Init(ctx, cfg.Sources.Kind, connection, cfg.Properties, b.log)
@@ -72,6 +180,41 @@ func (b *Binder) Init(ctx context.Context, cfg config.BindingConfig, exporter *m
 		}
 		b.sources = append(b.sources, source)
 	}
+	if exporter != nil {
+		var pools []*pool.Pool
+		for _, source := range b.sources {
+			if ps, ok := source.(sources.PoolSource); ok {
+				pools = append(pools, ps.Pools()...)
+			}
+		}
+		if b.shadowPool != nil {
+			pools = append(pools, b.shadowPool)
+		}
+		if len(pools) > 0 {
+			exporter.AddPools(b.name, cfg.Sources.Kind, pools)
+		}
+		var caches []*cache.Cache
+		for _, target := range b.targets {
+			if ct, ok := target.(targets.CacheTarget); ok && ct.Cache() != nil {
+				caches = append(caches, ct.Cache())
+			}
+		}
+		if len(caches) > 0 {
+			exporter.AddCaches(b.name, cfg.Targets.Kind, caches)
+		}
+		var generators []*generator.Generator
+		for _, source := range b.sources {
+			if gs, ok := source.(sources.GeneratorSource); ok {
+				generators = append(generators, gs.Generators()...)
+			}
+		}
+		if len(generators) > 0 {
+			exporter.AddGenerators(b.name, cfg.Sources.Kind, generators)
+		}
+		if b.gapCheck != nil {
+			exporter.AddChecker(b.name, cfg.Sources.Kind, b.gapCheck.Checker())
+		}
+	}
 	b.log.Infof("binding %s initialized successfully", b.name)
 	return nil
 }
@@ -84,6 +227,9 @@ func (b *Binder) Start(ctx context.Context) error {
 		return fmt.Errorf("error starting binding connector %s,no valid initialzed sources found", b.name)
 	}
 
+	if b.shadowPool != nil {
+		b.shadowPool.Start(ctx)
+	}
 	for _, source := range b.sources {
 		err := source.Start(ctx, b.targetsMiddleware)
 		if err != nil {
@@ -107,6 +253,57 @@ func (b *Binder) Stop() error {
 			return err
 		}
 	}
+	if b.shadowPool != nil {
+		b.shadowPool.Stop()
+	}
+	if b.pipeline != nil {
+		if err := b.pipeline.Close(); err != nil {
+			return err
+		}
+	}
+	if b.audit != nil {
+		if err := b.audit.Close(); err != nil {
+			return err
+		}
+	}
+	if b.exporter != nil {
+		b.exporter.RemovePools(b.name)
+		b.exporter.RemoveCaches(b.name)
+		b.exporter.RemoveGenerators(b.name)
+		b.exporter.RemoveChecker(b.name)
+	}
 	b.log.Infof("binding %s stopped successfully", b.name)
 	return nil
 }
+
+// Streams returns the supervised streams of the binding targets.
+func (b *Binder) Streams() []*supervisor.Supervisor {
+	var list []*supervisor.Supervisor
+	for _, target := range b.targets {
+		if st, ok := target.(targets.StreamTarget); ok {
+			list = append(list, st.Streams()...)
+		}
+	}
+	return list
+}
+
+func (b *Binder) checkpointSources() []sources.CheckpointSource {
+	var list []sources.CheckpointSource
+	for _, source := range b.sources {
+		if cs, ok := source.(sources.CheckpointSource); ok {
+			if _, enabled := cs.Checkpoint(); enabled {
+				list = append(list, cs)
+			}
+		}
+	}
+	return list
+}
+
+func (b *Binder) Checkpoints() []checkpoint.Checkpoint {
+	var list []checkpoint.Checkpoint
+	for _, cs := range b.checkpointSources() {
+		cp, _ := cs.Checkpoint()
+		list = append(list, cp)
+	}
+	return list
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/checkpoint"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
 	"net/http"
@@ -58,31 +59,34 @@ func (s *Service) Start(ctx context.Context, cfg *config.Config) error {
 			err := s.Add(ctx, cfg)
 			if err == nil {
 				return
-			} else {
-				s.log.Errorf("failed to initialized binding, %s", err.Error())
-			}
-			count := 0
-			for {
-				select {
-				case <-time.After(addRetryInterval):
-					count++
-					err := s.Add(ctx, cfg)
-					if err != nil {
-						s.log.Errorf("failed to initialized binding: %s, attempt: %d, error: %s", cfg.Name, count, err.Error())
-					} else {
-						return
-					}
-				case <-ctx.Done():
-					return
-				}
 			}
-
+			s.log.Errorf("failed to initialized binding, %s", err.Error())
+			s.retryAdd(ctx, cfg)
 		}(s.currentCtx, bindingCfg)
 
 	}
 	return nil
 }
 
+// retryAdd adds the binding every addRetryInterval until it is added or ctx is done.
+func (s *Service) retryAdd(ctx context.Context, cfg config.BindingConfig) {
+	count := 0
+	for {
+		select {
+		case <-time.After(addRetryInterval):
+			count++
+			err := s.Add(ctx, cfg)
+			if err != nil {
+				s.log.Errorf("failed to initialized binding: %s, attempt: %d, error: %s", cfg.Name, count, err.Error())
+			} else {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (s *Service) Stop() {
 	if s.currentCancelFunc != nil {
 		s.currentCancelFunc()
@@ -106,12 +110,13 @@ func (s *Service) Add(ctx context.Context, cfg config.BindingConfig) error {
 	if err != nil {
 		return err
 	}
+	status.watchStreams(binder.Streams())
 	err = binder.Start(ctx)
 	if err != nil {
 		return err
 	}
 	s.bindings.Store(cfg.Name, binder)
-	status.Ready = true
+	status.setReady(true)
 	s.bindingStatus.Store(cfg.Name, status)
 	return nil
 }
@@ -135,15 +140,66 @@ func (s *Service) PrometheusHandler() http.Handler {
 	return s.exporter.PrometheusHandler()
 }
 func (s *Service) Stats() []*metrics.Report {
-	return s.exporter.Store.List()
+	return s.exporter.Reports()
 }
 func (s *Service) GetStatus() []*Status {
 	var list []*Status
 	for _, binding := range s.cfg.Bindings {
 		val, ok := s.bindingStatus.Load(binding.Name)
 		if ok {
-			list = append(list, val.(*Status))
+			list = append(list, val.(*Status).snapshot())
 		}
 	}
 	return list
 }
+
+func (s *Service) Checkpoints(name string) ([]checkpoint.Checkpoint, error) {
+	val, ok := s.bindings.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("binding %s not found", name)
+	}
+	return val.(*Binder).Checkpoints(), nil
+}
+
+// RewindCheckpoints restarts the binding so its sources resume from sequence. A zero sequence
+// deletes the checkpoints and the sources start from their start_from setting.
+func (s *Service) RewindCheckpoints(name string, sequence uint64) error {
+	val, ok := s.bindings.Load(name)
+	if !ok {
+		return fmt.Errorf("binding %s not found", name)
+	}
+	binder := val.(*Binder)
+	var bindingCfg *config.BindingConfig
+	for i := range s.cfg.Bindings {
+		if s.cfg.Bindings[i].Name == name {
+			bindingCfg = &s.cfg.Bindings[i]
+		}
+	}
+	if bindingCfg == nil {
+		return fmt.Errorf("binding %s configuration not found", name)
+	}
+	list := binder.checkpointSources()
+	if len(list) == 0 {
+		return fmt.Errorf("binding %s has no checkpoint enabled sources", name)
+	}
+	if err := s.Remove(name); err != nil {
+		return err
+	}
+	for _, cs := range list {
+		var err error
+		if sequence == 0 {
+			err = cs.ResetCheckpoint()
+		} else {
+			err = cs.Rewind(sequence)
+		}
+		if err != nil {
+			s.log.Errorf("error updating checkpoint of binding %s, %s", name, err.Error())
+		}
+	}
+	if err := s.Add(s.currentCtx, *bindingCfg); err != nil {
+		// the binding was removed, keep retrying so it is not lost until the next restart
+		go s.retryAdd(s.currentCtx, *bindingCfg)
+		return fmt.Errorf("error restarting binding %s, retrying, %w", name, err)
+	}
+	return nil
+}
@@ -2,15 +2,19 @@ package binding
 
 import (
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
+	"sync"
 )
 
 type Status struct {
-	Binding      string            `json:"binding"`
-	Ready        bool              `json:"ready"`
-	SourceType   string            `json:"source_type"`
-	SourceConfig []config.Metadata `json:"source_config"`
-	TargetType   string            `json:"target_type"`
-	TargetConfig []config.Metadata `json:"target_config"`
+	mu           sync.Mutex
+	Binding      string             `json:"binding"`
+	Ready        bool               `json:"ready"`
+	SourceType   string             `json:"source_type"`
+	SourceConfig []config.Metadata  `json:"source_config"`
+	TargetType   string             `json:"target_type"`
+	TargetConfig []config.Metadata  `json:"target_config"`
+	Streams      []supervisor.Stats `json:"streams,omitempty"`
 }
 
 func newStatus(cfg config.BindingConfig) *Status {
@@ -23,3 +27,38 @@ func newStatus(cfg config.BindingConfig) *Status {
 		TargetConfig: cfg.Targets.Connections,
 	}
 }
+
+func (s *Status) setReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Ready = ready
+}
+
+// watchStreams keeps the state of the binding target streams up to date.
+func (s *Status) watchStreams(streams []*supervisor.Supervisor) {
+	s.mu.Lock()
+	s.Streams = make([]supervisor.Stats, len(streams))
+	s.mu.Unlock()
+	for i, stream := range streams {
+		index := i
+		stream.OnState(func(stats supervisor.Stats) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.Streams[index] = stats
+		})
+	}
+}
+
+func (s *Status) snapshot() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Status{
+		Binding:      s.Binding,
+		Ready:        s.Ready,
+		SourceType:   s.SourceType,
+		SourceConfig: s.SourceConfig,
+		TargetType:   s.TargetType,
+		TargetConfig: s.TargetConfig,
+		Streams:      append([]supervisor.Stats(nil), s.Streams...),
+	}
+}
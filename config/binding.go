@@ -5,10 +5,11 @@ import (
 )
 
 type BindingConfig struct {
-	Name       string   `json:"name"`
-	Sources    Spec     `json:"sources"`
-	Targets    Spec     `json:"targets"`
-	Properties Metadata `json:"properties"`
+	Name        string             `json:"name"`
+	Sources     Spec               `json:"sources"`
+	Targets     Spec               `json:"targets"`
+	Properties  Metadata           `json:"properties"`
+	Middlewares []MiddlewareConfig `json:"middlewares"`
 }
 
 func (b BindingConfig) Validate() error {
@@ -21,5 +22,19 @@ func (b BindingConfig) Validate() error {
 	if err := b.Targets.Validate(); err != nil {
 		return fmt.Errorf("binding targets error, %w", err)
 	}
+	primaries := 0
+	for _, connection := range b.Targets.Connections {
+		if !connection.ParseBool("shadow", false) {
+			primaries++
+		}
+	}
+	if primaries == 0 {
+		return fmt.Errorf("binding targets error, at least one non shadow target is required")
+	}
+	for _, md := range b.Middlewares {
+		if err := md.Validate(len(b.Targets.Connections)); err != nil {
+			return fmt.Errorf("binding middlewares error, %w", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+)
+
+type MiddlewareConfig struct {
+	Kind       string   `json:"kind"`
+	Targets    []int    `json:"targets"`
+	Properties Metadata `json:"properties"`
+}
+
+func (m MiddlewareConfig) Validate(targets int) error {
+	if m.Kind == "" {
+		return fmt.Errorf("kind cannot be empty")
+	}
+	for _, index := range m.Targets {
+		if index < 0 || index >= targets {
+			return fmt.Errorf("invalid target index %d for middleware %s", index, m.Kind)
+		}
+	}
+	return nil
+}
+
+func (m MiddlewareConfig) AppliesTo(target int) bool {
+	if len(m.Targets) == 0 {
+		return true
+	}
+	for _, index := range m.Targets {
+		if index == target {
+			return true
+		}
+	}
+	return false
+}
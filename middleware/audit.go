@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"math"
+	"time"
+)
+
+const (
+	defaultAuditMaxSize    = 100
+	defaultAuditMaxBackups = 5
+)
+
+type AuditRecord struct {
+	Time          string `json:"time"`
+	Binding       string `json:"binding"`
+	Target        int    `json:"target"`
+	TargetAddress string `json:"target_address,omitempty"`
+	ID            string `json:"id"`
+	Channel       string `json:"channel"`
+	BodyHash      string `json:"body_hash,omitempty"`
+	Body          []byte `json:"body,omitempty"`
+	Result        string `json:"result"`
+	Error         string `json:"error,omitempty"`
+	LatencyMs     int64  `json:"latency_ms"`
+}
+
+type AuditMiddleware struct {
+	binding     string
+	includeBody bool
+	writer      *logger.LogRotator
+	cancel      context.CancelFunc
+	log         *logger.Logger
+}
+
+func NewAuditMiddleware(binding string, meta config.Metadata, log *logger.Logger) (*AuditMiddleware, error) {
+	filename, err := meta.MustParseString("audit_file")
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit file value, %w", err)
+	}
+	maxSize, err := meta.ParseIntWithRange("audit_max_size", defaultAuditMaxSize, 1, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit max size value, %w", err)
+	}
+	maxBackups, err := meta.ParseIntWithRange("audit_max_backups", defaultAuditMaxBackups, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit max backups value, %w", err)
+	}
+	maxAge, err := meta.ParseIntWithRange("audit_max_age", 0, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit max age value, %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AuditMiddleware{
+		binding:     binding,
+		includeBody: meta.ParseBool("audit_include_body", false),
+		writer: &logger.LogRotator{
+			Ctx:        ctx,
+			Filename:   filename,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAge,
+			Compress:   meta.ParseBool("audit_compress", false),
+		},
+		cancel: cancel,
+		log:    log,
+	}, nil
+}
+
+func (a *AuditMiddleware) Record(request interface{}, target int, address string, latency time.Duration, err error) AuditRecord {
+	record := AuditRecord{
+		Time:          time.Now().UTC().Format(time.RFC3339Nano),
+		Binding:       a.binding,
+		Target:        target,
+		TargetAddress: address,
+		Result:        "success",
+		LatencyMs:     latency.Milliseconds(),
+	}
+	if err != nil {
+		record.Result = "error"
+		record.Error = err.Error()
+	}
+	if msg, msgErr := message.From(request); msgErr == nil {
+		record.ID = msg.ID
+		record.Channel = msg.Channel
+		hash := sha256.Sum256(msg.Body)
+		record.BodyHash = "sha256:" + hex.EncodeToString(hash[:])
+		if a.includeBody {
+			record.Body = msg.Body
+		}
+	}
+	return record
+}
+
+func (a *AuditMiddleware) Write(record AuditRecord) error {
+	data, err := logJson.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = a.writer.Write(append(data, '\n'))
+	return err
+}
+
+func (a *AuditMiddleware) Close() error {
+	a.cancel()
+	return a.writer.Close()
+}
+
+// Audit writes a record for every call to the next middleware, so each retry attempt is recorded.
+func Audit(a *AuditMiddleware, target int, address string) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			start := time.Now()
+			result, err := df.Do(ctx, request)
+			if writeErr := a.Write(a.Record(request, target, address, time.Since(start), err)); writeErr != nil && a.log != nil {
+				a.log.Errorf("error writing audit record, %s", writeErr.Error())
+			}
+			return result, err
+		})
+	}
+}
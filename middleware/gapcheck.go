@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+// GapCheckMiddleware checks the sequences of the messages generated by timer sources of other
+// bridges and counts the missing messages per timer name.
+type GapCheckMiddleware struct {
+	checker *generator.Checker
+	log     *logger.Logger
+}
+
+const (
+	defaultGapCheckWindow      = 100
+	defaultGapCheckIdleSeconds = 600
+)
+
+func NewGapCheckMiddleware(meta config.Metadata, log *logger.Logger) (*GapCheckMiddleware, error) {
+	window, err := meta.ParseIntWithRange("timer_gap_window", defaultGapCheckWindow, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timer gap window value, %w", err)
+	}
+	idle, err := meta.ParseIntWithRange("timer_gap_idle_seconds", defaultGapCheckIdleSeconds, 1, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timer gap idle seconds value, %w", err)
+	}
+	return &GapCheckMiddleware{
+		checker: generator.NewChecker(uint64(window), time.Duration(idle)*time.Second),
+		log:     log,
+	}, nil
+}
+
+func (g *GapCheckMiddleware) Checker() *generator.Checker {
+	return g.checker
+}
+
+// Check records the sequence of a generated message. Messages without valid timer tags are ignored.
+func (g *GapCheckMiddleware) Check(request interface{}) {
+	msg, err := message.From(request)
+	if err != nil {
+		return
+	}
+	runId := msg.Tags[generator.TagRunId]
+	if runId == "" {
+		return
+	}
+	sequence, err := strconv.ParseUint(msg.Tags[generator.TagSequence], 10, 64)
+	if err != nil {
+		return
+	}
+	name := msg.Tags[generator.TagName]
+	if missing := g.checker.CheckNamed(name, runId, sequence); missing > 0 {
+		g.log.Warnf("%d messages of timer %s run %s missing at sequence %d", missing, name, runId, sequence)
+	}
+}
+
+func GapCheck(g *GapCheckMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			g.Check(request)
+			return df.Do(ctx, request)
+		})
+	}
+}
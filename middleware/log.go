@@ -2,8 +2,13 @@ package middleware
 
 import (
 	"fmt"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"math"
+	"strings"
+	"time"
 )
 
 type LogLevelType string
@@ -15,6 +20,11 @@ const (
 	LogLevelTypeError LogLevelType = "error"
 )
 
+const (
+	defaultLogMaxBodySize = 256
+	redactedValue         = "***"
+)
+
 var logLevelMap = map[string]string{
 	"debug": "debug",
 	"info":  "info",
@@ -22,8 +32,15 @@ var logLevelMap = map[string]string{
 	"":      "",
 }
 
+var logJson = jsoniter.ConfigCompatibleWithStandardLibrary
+
 type LogMiddleware struct {
-	minLevel LogLevelType
+	minLevel       LogLevelType
+	binding        string
+	maxBodySize    int
+	redactTags     map[string]bool
+	redactMetadata []string
+	redactBody     []string
 	*logger.Logger
 }
 
@@ -32,9 +49,21 @@ func NewLogMiddleware(name string, meta config.Metadata) (*LogMiddleware, error)
 	if err != nil {
 		return nil, fmt.Errorf("invalid log level value, %w", err)
 	}
+	maxBodySize, err := meta.ParseIntWithRange("log_max_body_size", defaultLogMaxBodySize, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log max body size value, %w", err)
+	}
 	lm := &LogMiddleware{
-		minLevel: LogLevelTypeNoLog,
-		Logger:   logger.NewLogger(name, level),
+		minLevel:       LogLevelTypeNoLog,
+		binding:        name,
+		maxBodySize:    maxBodySize,
+		redactTags:     map[string]bool{},
+		redactMetadata: trimList(meta.ParseStringList("log_redact_metadata")),
+		redactBody:     trimList(meta.ParseStringList("log_redact_body")),
+		Logger:         logger.NewLogger(name, level),
+	}
+	for _, tag := range trimList(meta.ParseStringList("log_redact_tags")) {
+		lm.redactTags[tag] = true
 	}
 	switch level {
 	case "debug":
@@ -46,3 +75,87 @@ func NewLogMiddleware(name string, meta config.Metadata) (*LogMiddleware, error)
 	}
 	return lm, nil
 }
+
+func trimList(list []string) []string {
+	var result []string
+	for _, item := range list {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Fields returns the structured log fields of a processed request. The body, tags and metadata
+// are added only when withContent is set, after applying the redaction rules.
+func (lm *LogMiddleware) Fields(request interface{}, latency time.Duration, err error, withContent bool) []interface{} {
+	fields := []interface{}{"binding", lm.binding, "latency", latency}
+	if err != nil {
+		fields = append(fields, "error", err.Error())
+	}
+	msg, msgErr := message.From(request)
+	if msgErr != nil {
+		return append(fields, "request_type", fmt.Sprintf("%T", request))
+	}
+	fields = append(fields, "channel", msg.Channel, "id", msg.ID, "body_size", len(msg.Body))
+	if !withContent {
+		return fields
+	}
+	tags := map[string]string{}
+	for key, value := range msg.Tags {
+		if lm.redactTags[key] {
+			value = redactedValue
+		}
+		tags[key] = value
+	}
+	fields = append(fields, "tags", tags, "metadata", redactJson(msg.Metadata, lm.redactMetadata))
+	if lm.maxBodySize > 0 {
+		body := redactJson(string(msg.Body), lm.redactBody)
+		if len(body) > lm.maxBodySize {
+			body = body[:lm.maxBodySize] + "..."
+			fields = append(fields, "body_truncated", true)
+		}
+		fields = append(fields, "body", body)
+	}
+	return fields
+}
+
+// redactJson masks the values at the dotted paths of a JSON document. Values which are not JSON
+// objects are returned as is when there is nothing to redact and fully masked otherwise, so
+// unparsable content never leaks.
+func redactJson(value string, paths []string) string {
+	if len(paths) == 0 || value == "" {
+		return value
+	}
+	var doc interface{}
+	if err := logJson.Unmarshal([]byte(value), &doc); err != nil {
+		return redactedValue
+	}
+	for _, path := range paths {
+		redactPath(doc, strings.Split(path, "."))
+	}
+	data, err := logJson.Marshal(doc)
+	if err != nil {
+		return redactedValue
+	}
+	return string(data)
+}
+
+func redactPath(doc interface{}, path []string) {
+	switch val := doc.(type) {
+	case map[string]interface{}:
+		child, ok := val[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			val[path[0]] = redactedValue
+			return
+		}
+		redactPath(child, path[1:])
+	case []interface{}:
+		for _, item := range val {
+			redactPath(item, path)
+		}
+	}
+}
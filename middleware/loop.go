@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+)
+
+const defaultLoopMaxHops = 8
+
+var LoopProvenanceFields = []string{provenance.FieldOrigin, provenance.FieldHops, provenance.FieldPath}
+
+type LoopMiddleware struct {
+	binding  string
+	target   string
+	prefix   string
+	maxHops  int
+	exporter *metrics.Exporter
+	report   *metrics.Report
+	log      *logger.Logger
+}
+
+func NewLoopMiddleware(cfg config.BindingConfig, target string, meta config.Metadata, exporter *metrics.Exporter, log *logger.Logger) (*LoopMiddleware, error) {
+	maxHops, err := meta.ParseIntWithRange("loop_max_hops", defaultLoopMaxHops, 0, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("invalid loop max hops value, %w", err)
+	}
+	return &LoopMiddleware{
+		binding:  cfg.Name,
+		target:   target,
+		prefix:   meta.ParseString("provenance_prefix", provenance.DefaultPrefix),
+		maxHops:  maxHops,
+		exporter: exporter,
+		report: &metrics.Report{
+			Key:        fmt.Sprintf("%s-%s-%s", cfg.Name, cfg.Sources.Kind, cfg.Targets.Kind),
+			Binding:    cfg.Name,
+			SourceKind: cfg.Sources.Kind,
+			TargetKind: cfg.Targets.Kind,
+		},
+		log: log,
+	}, nil
+}
+
+// Check returns a reason when the message already passed through this binding or the target
+// cluster, or when it exceeded the max hops limit.
+func (l *LoopMiddleware) Check(request interface{}) (string, error) {
+	msg, err := message.From(request)
+	if err != nil {
+		return "", err
+	}
+	if l.maxHops > 0 && provenance.Hops(msg, l.prefix) >= l.maxHops {
+		return fmt.Sprintf("max hops %d reached", l.maxHops), nil
+	}
+	if l.target != "" && msg.Tags[l.prefix+provenance.FieldOrigin] == l.target {
+		return fmt.Sprintf("target cluster %s is the message origin", l.target), nil
+	}
+	for _, hop := range provenance.Path(msg, l.prefix) {
+		if hop.Instance == provenance.InstanceID() && hop.Binding == l.binding {
+			return fmt.Sprintf("message already forwarded by binding %s", l.binding), nil
+		}
+		if l.target != "" && (hop.Source == l.target || hop.Target == l.target) {
+			return fmt.Sprintf("target cluster %s already visited", l.target), nil
+		}
+	}
+	return "", nil
+}
+
+func (l *LoopMiddleware) drop(reason string) {
+	l.log.Debugf("message dropped by loop prevention, %s", reason)
+	if l.exporter != nil {
+		l.exporter.Report(&metrics.Report{
+			Key:               l.report.Key,
+			Binding:           l.report.Binding,
+			SourceKind:        l.report.SourceKind,
+			TargetKind:        l.report.TargetKind,
+			DroppedLoopsCount: 1,
+		})
+	}
+}
+
+func Loop(l *LoopMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			reason, err := l.Check(request)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				l.drop(reason)
+				return &DroppedResponse{Reason: reason}, nil
+			}
+			return df.Do(ctx, request)
+		})
+	}
+}
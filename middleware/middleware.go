@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/pkg/retry"
 	"reflect"
+	"time"
 )
 
 type Middleware interface {
@@ -18,23 +20,34 @@ func (df DoFunc) Do(ctx context.Context, request interface{}) (interface{}, erro
 
 type MiddlewareFunc func(Middleware) Middleware
 
+// DroppedResponse is returned by a middleware that drops a request before it reaches the target,
+// so sources that reply to the sender can tell it apart from an executed request.
+type DroppedResponse struct {
+	Reason string
+}
+
+func (d *DroppedResponse) Err() error {
+	return fmt.Errorf("request dropped, %s", d.Reason)
+}
+
 func Log(log *LogMiddleware) MiddlewareFunc {
 	return func(df Middleware) Middleware {
 		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			start := time.Now()
 			result, err := df.Do(ctx, request)
+			latency := time.Since(start)
 			switch log.minLevel {
 			case "debug":
-
-				log.Infof("request: %v, response: %v, error:%+v", request, result, err)
+				log.Debugw("request processed", log.Fields(request, latency, err, true)...)
 			case "info":
 				if err != nil {
-					log.Errorf("error processing request: %s", err.Error())
+					log.Errorw("error processing request", log.Fields(request, latency, err, false)...)
 				} else {
-					log.Infof("request processed with successful response")
+					log.Infow("request processed with successful response", log.Fields(request, latency, nil, false)...)
 				}
 			case "error":
 				if err != nil {
-					log.Errorf("error processing request: %s", err.Error())
+					log.Errorw("error processing request", log.Fields(request, latency, err, false)...)
 				}
 			}
 			return result, err
@@ -4,10 +4,18 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
 	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+	"github.com/kubemq-io/kubemq-go"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
 	"math"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -503,6 +511,55 @@ func TestClient_Log(t *testing.T) {
 	}
 }
 
+func TestClient_LogFields(t *testing.T) {
+	log, err := NewLogMiddleware("binding-1", map[string]string{
+		"log_level":           "debug",
+		"log_max_body_size":   "40",
+		"log_redact_tags":     "token",
+		"log_redact_metadata": "auth",
+		"log_redact_body":     "user.password,cards.number",
+	})
+	require.NoError(t, err)
+	event := &kubemq.Event{
+		Id:       "id-1",
+		Channel:  "ch-1",
+		Metadata: `{"auth":"secret","type":"order"}`,
+		Body:     []byte(`{"user":{"name":"a","password":"p"},"cards":[{"number":"1234"}]}`),
+		Tags:     map[string]string{"token": "abc", "region": "eu"},
+	}
+	fields := log.Fields(event, time.Second, fmt.Errorf("some-error"), true)
+	values := map[string]interface{}{}
+	for i := 0; i < len(fields); i += 2 {
+		values[fields[i].(string)] = fields[i+1]
+	}
+	require.Equal(t, "binding-1", values["binding"])
+	require.Equal(t, "ch-1", values["channel"])
+	require.Equal(t, "id-1", values["id"])
+	require.Equal(t, len(event.Body), values["body_size"])
+	require.Equal(t, time.Second, values["latency"])
+	require.Equal(t, "some-error", values["error"])
+	require.Equal(t, map[string]string{"token": "***", "region": "eu"}, values["tags"])
+	require.Equal(t, `{"auth":"***","type":"order"}`, values["metadata"])
+	require.Equal(t, true, values["body_truncated"])
+	require.Equal(t, `{"cards":[{"number":"***"}],"user":{"nam`+"...", values["body"])
+	require.NotContains(t, values["body"], "1234")
+
+	fields = log.Fields(event, time.Second, nil, false)
+	require.NotContains(t, fields, "body")
+	require.NotContains(t, fields, "tags")
+	require.NotContains(t, fields, "error")
+
+	fields = log.Fields("request", time.Second, nil, true)
+	require.Contains(t, fields, "request_type")
+}
+
+func TestClient_RedactJson(t *testing.T) {
+	require.Equal(t, "plain", redactJson("plain", nil))
+	require.Equal(t, "***", redactJson("plain", []string{"a"}))
+	require.Equal(t, `{"a":{"b":"***"}}`, redactJson(`{"a":{"b":1}}`, []string{"a.b"}))
+	require.Equal(t, `{"a":1}`, redactJson(`{"a":1}`, []string{"b.c"}))
+}
+
 func TestClient_Chain(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -532,3 +589,771 @@ func TestClient_Chain(t *testing.T) {
 	d := time.Since(start)
 	require.GreaterOrEqual(t, d.Milliseconds(), 2*time.Second.Milliseconds())
 }
+
+func TestClient_Pipeline(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          config.BindingConfig
+		index        int
+		wantExecuted int
+		wantErr      bool
+	}{
+		{
+			name: "retry stage for all targets",
+			cfg: config.BindingConfig{
+				Name: "pipeline",
+				Middlewares: []config.MiddlewareConfig{
+					{
+						Kind: "retry",
+						Properties: map[string]string{
+							"retry_attempts":           "3",
+							"retry_delay_milliseconds": "10",
+							"retry_delay_type":         "fixed",
+						},
+					},
+					{
+						Kind:       "log",
+						Properties: map[string]string{"log_level": "error"},
+					},
+				},
+			},
+			index:        1,
+			wantExecuted: 3,
+			wantErr:      false,
+		},
+		{
+			name: "retry stage twice",
+			cfg: config.BindingConfig{
+				Name: "pipeline",
+				Middlewares: []config.MiddlewareConfig{
+					{
+						Kind: "retry",
+						Properties: map[string]string{
+							"retry_attempts":           "2",
+							"retry_delay_milliseconds": "10",
+							"retry_delay_type":         "fixed",
+						},
+					},
+					{
+						Kind: "retry",
+						Properties: map[string]string{
+							"retry_attempts":           "2",
+							"retry_delay_milliseconds": "10",
+							"retry_delay_type":         "fixed",
+						},
+					},
+				},
+			},
+			index:        0,
+			wantExecuted: 4,
+			wantErr:      false,
+		},
+		{
+			name: "retry stage for another target",
+			cfg: config.BindingConfig{
+				Name: "pipeline",
+				Middlewares: []config.MiddlewareConfig{
+					{
+						Kind:    "retry",
+						Targets: []int{1},
+						Properties: map[string]string{
+							"retry_attempts":           "3",
+							"retry_delay_milliseconds": "10",
+							"retry_delay_type":         "fixed",
+						},
+					},
+				},
+			},
+			index:        0,
+			wantExecuted: 1,
+			wantErr:      false,
+		},
+		{
+			name: "invalid stage kind",
+			cfg: config.BindingConfig{
+				Name: "pipeline",
+				Middlewares: []config.MiddlewareConfig{
+					{
+						Kind: "bad-kind",
+					},
+				},
+			},
+			index:   0,
+			wantErr: true,
+		},
+		{
+			name: "invalid stage properties",
+			cfg: config.BindingConfig{
+				Name: "pipeline",
+				Middlewares: []config.MiddlewareConfig{
+					{
+						Kind:       "rate-limiter",
+						Properties: map[string]string{"rate_per_second": "-1"},
+					},
+				},
+			},
+			index:   0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			executed := 0
+			target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+				executed++
+				return nil, fmt.Errorf("some-error")
+			})
+			p, err := NewPipeline(tt.cfg, nil, logger.NewLogger("pipeline"))
+			require.NoError(t, err)
+			md, err := p.Build(target, tt.index)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = md.Do(ctx, "request")
+			require.Error(t, err)
+			require.Equal(t, tt.wantExecuted, executed)
+		})
+	}
+}
+
+func TestClient_PipelineLoopPrevention(t *testing.T) {
+	targets := config.Spec{
+		Kind:        "kubemq.events",
+		Connections: []config.Metadata{{"address": "cluster-b:50000"}, {"address": "cluster-c:50000"}},
+	}
+	tests := []struct {
+		name        string
+		middlewares []config.MiddlewareConfig
+		wantErr     bool
+	}{
+		{
+			name:        "provenance before loop prevention",
+			middlewares: []config.MiddlewareConfig{{Kind: "provenance"}, {Kind: "loop-prevention"}},
+		},
+		{
+			name:        "loop prevention before provenance",
+			middlewares: []config.MiddlewareConfig{{Kind: "loop-prevention"}, {Kind: "provenance"}},
+			wantErr:     true,
+		},
+		{
+			name:        "no provenance",
+			middlewares: []config.MiddlewareConfig{{Kind: "loop-prevention"}},
+			wantErr:     true,
+		},
+		{
+			name:        "provenance of another target",
+			middlewares: []config.MiddlewareConfig{{Kind: "provenance", Targets: []int{1}}, {Kind: "loop-prevention"}},
+			wantErr:     true,
+		},
+		{
+			name:        "provenance and loop prevention of the same target",
+			middlewares: []config.MiddlewareConfig{{Kind: "provenance", Targets: []int{0}}, {Kind: "loop-prevention", Targets: []int{0}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.BindingConfig{
+				Name:        "a-to-b",
+				Sources:     config.Spec{Kind: "kubemq.events", Connections: []config.Metadata{{"address": "cluster-a:50000"}}},
+				Targets:     targets,
+				Middlewares: tt.middlewares,
+			}
+			p, err := NewPipeline(cfg, nil, logger.NewLogger("pipeline"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			executed := 0
+			target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+				executed++
+				return request, nil
+			})
+			md, err := p.Build(target, 0)
+			require.NoError(t, err)
+			result, err := md.Do(context.Background(), kubemq.NewEvent().SetId("id").SetChannel("events"))
+			require.NoError(t, err)
+			require.IsType(t, &kubemq.Event{}, result)
+			require.Equal(t, 1, executed)
+		})
+	}
+}
+
+func TestClient_Script(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     config.Metadata
+		target   int
+		req      interface{}
+		wantReq  *kubemq.Event
+		wantDrop bool
+		wantErr  bool
+		wantInit bool
+	}{
+		{
+			name: "set tag from body",
+			meta: map[string]string{
+				"script": `
+def process(msg):
+    data = json.decode(msg["body"])
+    msg["tags"]["route"] = data["region"] + "-" + data["type"]
+    msg["channel"] = "events.routed"
+    return msg
+`,
+			},
+			req: kubemq.NewEvent().SetId("id").SetChannel("events").SetBody([]byte(`{"region":"eu","type":"order"}`)),
+			wantReq: kubemq.NewEvent().SetId("id").SetChannel("events.routed").
+				SetBody([]byte(`{"region":"eu","type":"order"}`)).
+				SetTags(map[string]string{"route": "eu-order"}),
+		},
+		{
+			name: "drop message",
+			meta: map[string]string{
+				"script": `
+def process(msg):
+    return None
+`,
+			},
+			req:      kubemq.NewEvent().SetId("id").SetChannel("events"),
+			wantDrop: true,
+		},
+		{
+			name: "route to other target",
+			meta: map[string]string{
+				"script": `
+def process(msg):
+    msg["targets"] = [1]
+    return msg
+`,
+			},
+			target:   0,
+			req:      kubemq.NewEvent().SetId("id").SetChannel("events"),
+			wantDrop: true,
+		},
+		{
+			name: "route to this target",
+			meta: map[string]string{
+				"script": `
+def process(msg):
+    msg["targets"] = [0, 1]
+    return msg
+`,
+			},
+			target:  1,
+			req:     kubemq.NewEvent().SetId("id").SetChannel("events"),
+			wantReq: kubemq.NewEvent().SetId("id").SetChannel("events").SetBody([]byte{}).SetTags(map[string]string{}),
+		},
+		{
+			name: "script failure",
+			meta: map[string]string{
+				"script": `
+def process(msg):
+    fail("invalid message")
+`,
+			},
+			req:     kubemq.NewEvent().SetId("id").SetChannel("events"),
+			wantErr: true,
+		},
+		{
+			name: "script timeout",
+			meta: map[string]string{
+				"script_timeout_milliseconds": "50",
+				"script": `
+def process(msg):
+    for i in range(1000000000):
+        pass
+    return msg
+`,
+			},
+			req:     kubemq.NewEvent().SetId("id").SetChannel("events"),
+			wantErr: true,
+		},
+		{
+			name: "bad script",
+			meta: map[string]string{
+				"script": `def process(msg)`,
+			},
+			wantInit: true,
+		},
+		{
+			name: "missing function",
+			meta: map[string]string{
+				"script":          `def other(msg): return msg`,
+				"script_function": "process",
+			},
+			wantInit: true,
+		},
+		{
+			name:     "no script",
+			meta:     map[string]string{},
+			wantInit: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			sm, err := NewScriptMiddleware("test", tt.meta)
+			if tt.wantInit {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var gotReq interface{}
+			target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+				gotReq = request
+				return nil, nil
+			})
+			md := Chain(target, Script(sm, tt.target))
+			resp, err := md.Do(ctx, tt.req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantDrop {
+				require.Nil(t, gotReq)
+				require.IsType(t, &DroppedResponse{}, resp)
+				return
+			}
+			require.EqualValues(t, tt.wantReq, gotReq)
+		})
+	}
+}
+
+func TestClient_Sampling(t *testing.T) {
+	tests := []struct {
+		name         string
+		meta         config.Metadata
+		requests     []interface{}
+		wantExecuted int
+		wantErr      bool
+	}{
+		{
+			name:         "no sampling",
+			meta:         map[string]string{},
+			requests:     []interface{}{kubemq.NewEvent(), kubemq.NewEvent(), kubemq.NewEvent()},
+			wantExecuted: 3,
+		},
+		{
+			name: "every 2nd message",
+			meta: map[string]string{
+				"sample_every": "2",
+			},
+			requests:     []interface{}{kubemq.NewEvent(), kubemq.NewEvent(), kubemq.NewEvent(), kubemq.NewEvent()},
+			wantExecuted: 2,
+		},
+		{
+			name: "zero percent",
+			meta: map[string]string{
+				"sample_percent": "0",
+			},
+			requests:     []interface{}{kubemq.NewEvent(), kubemq.NewEvent()},
+			wantExecuted: 0,
+		},
+		{
+			name: "consistent sampling by tag",
+			meta: map[string]string{
+				"sample_percent": "50",
+				"sample_key":     "tag:key",
+			},
+			requests: []interface{}{
+				kubemq.NewEvent().SetTags(map[string]string{"key": "a"}),
+				kubemq.NewEvent().SetTags(map[string]string{"key": "a"}),
+				kubemq.NewEvent().SetTags(map[string]string{"key": "a"}),
+			},
+		},
+		{
+			name: "bad percent",
+			meta: map[string]string{
+				"sample_percent": "101",
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad key",
+			meta: map[string]string{
+				"sample_key": "bad-key",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			sm, err := NewSamplingMiddleware(tt.meta)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			executed := 0
+			target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+				executed++
+				return nil, nil
+			})
+			md := Chain(target, Sampling(sm))
+			dropped := 0
+			for _, req := range tt.requests {
+				resp, err := md.Do(ctx, req)
+				require.NoError(t, err)
+				if _, ok := resp.(*DroppedResponse); ok {
+					dropped++
+				}
+			}
+			require.Equal(t, len(tt.requests), executed+dropped)
+			if tt.meta["sample_key"] != "" {
+				require.True(t, executed == 0 || executed == len(tt.requests))
+				return
+			}
+			require.Equal(t, tt.wantExecuted, executed)
+		})
+	}
+}
+
+func TestClient_Shadow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := pool.New("shadow", 1, 1)
+	p.Start(ctx)
+	defer p.Stop()
+	shadowCh := make(chan interface{}, 1)
+	release := make(chan struct{})
+	shadow := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		<-release
+		shadowCh <- request
+		return nil, fmt.Errorf("shadow-error")
+	})
+	primary := &mockTarget{
+		setResponse: "response",
+	}
+	md := Chain(primary, Shadow([]Middleware{shadow}, p, logger.NewLogger("shadow")))
+	start := time.Now()
+	resp, err := md.Do(ctx, "request")
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Less(t, time.Since(start), time.Second)
+	close(release)
+	select {
+	case req := <-shadowCh:
+		require.Equal(t, "request", req)
+	case <-ctx.Done():
+		require.NoError(t, ctx.Err())
+	}
+}
+
+func TestClient_Loop(t *testing.T) {
+	cfg := config.BindingConfig{
+		Name:    "b-to-a",
+		Sources: config.Spec{Kind: "source.events"},
+		Targets: config.Spec{Kind: "target.events"},
+	}
+	stamp := func(tags map[string]string, hops ...provenance.Hop) map[string]string {
+		msg := &message.Message{Tags: tags}
+		fields, _ := provenance.ParseFields(nil)
+		for _, hop := range hops {
+			provenance.Stamp(msg, hop, provenance.Options{Prefix: provenance.DefaultPrefix, Fields: fields})
+		}
+		return msg.Tags
+	}
+	tests := []struct {
+		name     string
+		target   string
+		meta     config.Metadata
+		req      *kubemq.Event
+		wantDrop bool
+		wantErr  bool
+	}{
+		{
+			name:     "new message",
+			target:   "cluster-a:50000",
+			meta:     map[string]string{},
+			req:      kubemq.NewEvent().SetTags(map[string]string{}),
+			wantDrop: false,
+		},
+		{
+			name:   "message from another cluster",
+			target: "cluster-c:50000",
+			meta:   map[string]string{},
+			req: kubemq.NewEvent().SetTags(stamp(map[string]string{},
+				provenance.Hop{Instance: "other", Binding: "a-to-b", Source: "cluster-a:50000", Target: "cluster-b:50000"})),
+			wantDrop: false,
+		},
+		{
+			name:   "target cluster already visited",
+			target: "cluster-a:50000",
+			meta:   map[string]string{},
+			req: kubemq.NewEvent().SetTags(stamp(map[string]string{},
+				provenance.Hop{Instance: "other", Binding: "a-to-b", Source: "cluster-a:50000", Target: "cluster-b:50000"})),
+			wantDrop: true,
+		},
+		{
+			name:   "already forwarded by this binding",
+			target: "cluster-c:50000",
+			meta:   map[string]string{},
+			req: kubemq.NewEvent().SetTags(stamp(map[string]string{},
+				provenance.Hop{Instance: provenance.InstanceID(), Binding: "b-to-a", Source: "cluster-d:50000", Target: "cluster-e:50000"})),
+			wantDrop: true,
+		},
+		{
+			name:   "max hops reached",
+			target: "cluster-z:50000",
+			meta: map[string]string{
+				"loop_max_hops": "2",
+			},
+			req: kubemq.NewEvent().SetTags(stamp(map[string]string{},
+				provenance.Hop{Instance: "other", Binding: "x", Source: "cluster-a:50000", Target: "cluster-b:50000"},
+				provenance.Hop{Instance: "other", Binding: "y", Source: "cluster-b:50000", Target: "cluster-c:50000"})),
+			wantDrop: true,
+		},
+		{
+			name:   "bad max hops",
+			target: "cluster-a:50000",
+			meta: map[string]string{
+				"loop_max_hops": "-1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			lm, err := NewLoopMiddleware(cfg, tt.target, tt.meta, nil, logger.NewLogger("loop"))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			executed := 0
+			target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+				executed++
+				return nil, nil
+			})
+			md := Chain(target, Loop(lm))
+			resp, err := md.Do(ctx, tt.req)
+			require.NoError(t, err)
+			if tt.wantDrop {
+				require.Equal(t, 0, executed)
+				require.IsType(t, &DroppedResponse{}, resp)
+			} else {
+				require.Equal(t, 1, executed)
+			}
+		})
+	}
+}
+
+func TestClient_GapCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tick := func(name, runId, sequence string) *kubemq.Event {
+		return kubemq.NewEvent().SetTags(map[string]string{
+			generator.TagName:     name,
+			generator.TagRunId:    runId,
+			generator.TagSequence: sequence,
+		})
+	}
+	gm, err := NewGapCheckMiddleware(config.Metadata{"timer_gap_window": "0"}, logger.NewLogger("gap-check"))
+	require.NoError(t, err)
+	executed := 0
+	target := DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		executed++
+		return nil, nil
+	})
+	md := Chain(target, GapCheck(gm))
+	requests := []interface{}{
+		tick("heartbeat", "run-1", "1"),
+		tick("heartbeat", "run-1", "2"),
+		tick("heartbeat", "run-1", "5"),
+		tick("heartbeat", "run-2", "1"),
+		tick("probe", "run-3", "10"),
+		tick("probe", "run-3", "12"),
+		tick("probe", "run-3", "bad-sequence"),
+		kubemq.NewEvent(),
+	}
+	for _, req := range requests {
+		_, err := md.Do(ctx, req)
+		require.NoError(t, err)
+	}
+	require.Equal(t, len(requests), executed)
+	require.Equal(t, map[string]uint64{"heartbeat": 2, "probe": 1}, gm.Checker().Gaps())
+
+	// the default window accepts messages reordered by concurrent workers
+	gm, err = NewGapCheckMiddleware(config.Metadata{}, logger.NewLogger("gap-check"))
+	require.NoError(t, err)
+	md = Chain(target, GapCheck(gm))
+	for _, sequence := range []string{"1", "3", "2", "5", "4", "6"} {
+		_, err := md.Do(ctx, tick("heartbeat", "run-1", sequence))
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[string]uint64{"heartbeat": 0}, gm.Checker().Gaps())
+
+	_, err = NewGapCheckMiddleware(config.Metadata{"timer_gap_idle_seconds": "0"}, logger.NewLogger("gap-check"))
+	require.Error(t, err)
+}
+
+func TestClient_Audit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	am, err := NewAuditMiddleware("binding-1", config.Metadata{
+		"audit_file":         filename,
+		"audit_include_body": "true",
+	}, logger.NewLogger("audit"))
+	require.NoError(t, err)
+	event := &kubemq.Event{Id: "id-1", Channel: "ch-1", Body: []byte("data")}
+	mock := &mockTarget{setError: fmt.Errorf("some-error")}
+	retry, err := NewRetryMiddleware(config.Metadata{"retry_attempts": "3", "retry_delay_milliseconds": "0", "retry_max_jitter_milliseconds": "1"}, logger.NewLogger("retry"))
+	require.NoError(t, err)
+	md := Chain(mock, Audit(am, 1, "localhost:50000"), Retry(retry))
+	_, err = md.Do(ctx, event)
+	require.Error(t, err)
+	md = Chain(&mockTarget{}, Audit(am, 0, "localhost:50001"))
+	_, err = md.Do(ctx, event)
+	require.NoError(t, err)
+	require.NoError(t, am.Close())
+
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 4)
+	var records []AuditRecord
+	for _, line := range lines {
+		record := AuditRecord{}
+		require.NoError(t, logJson.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	for _, record := range records[:3] {
+		require.Equal(t, "binding-1", record.Binding)
+		require.Equal(t, 1, record.Target)
+		require.Equal(t, "id-1", record.ID)
+		require.Equal(t, "ch-1", record.Channel)
+		require.Equal(t, "error", record.Result)
+		require.Equal(t, "some-error", record.Error)
+		require.Equal(t, []byte("data"), record.Body)
+		require.Equal(t, "sha256:3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7", record.BodyHash)
+	}
+	require.Equal(t, "success", records[3].Result)
+	require.Equal(t, "localhost:50001", records[3].TargetAddress)
+
+	_, err = NewAuditMiddleware("binding-1", config.Metadata{}, nil)
+	require.Error(t, err)
+}
+
+func TestClient_ScatterGather(t *testing.T) {
+	ok := func(body string, delay time.Duration) Middleware {
+		return &mockTarget{setResponse: &kubemq.QueryResponse{Executed: true, Body: []byte(body)}, delay: delay}
+	}
+	fail := func(delay time.Duration) Middleware {
+		return &mockTarget{setError: fmt.Errorf("target-error"), delay: delay}
+	}
+	dropped := &mockTarget{setResponse: &DroppedResponse{Reason: "filtered by script"}}
+	tests := []struct {
+		name     string
+		meta     config.Metadata
+		targets  []Middleware
+		wantBody string
+		wantErr  bool
+		maxTime  time.Duration
+	}{
+		{
+			name:     "first-success",
+			meta:     config.Metadata{"response_strategy": "first-success"},
+			targets:  []Middleware{ok("slow", time.Second), fail(0), ok("fast", 10*time.Millisecond)},
+			wantBody: "fast",
+			maxTime:  500 * time.Millisecond,
+		},
+		{
+			name:    "first-success - all failed",
+			meta:    config.Metadata{"response_strategy": "first-success"},
+			targets: []Middleware{fail(0), fail(0)},
+			wantErr: true,
+		},
+		{
+			name:     "quorum",
+			meta:     config.Metadata{"response_strategy": "quorum", "response_quorum": "2"},
+			targets:  []Middleware{ok("a", 10*time.Millisecond), fail(0), ok("b", 50*time.Millisecond), ok("c", time.Second)},
+			wantBody: "a",
+			maxTime:  500 * time.Millisecond,
+		},
+		{
+			name:    "quorum - not reached",
+			meta:    config.Metadata{"response_strategy": "quorum", "response_quorum": "2"},
+			targets: []Middleware{ok("a", 0), fail(0), fail(0)},
+			wantErr: true,
+		},
+		{
+			name:     "failover",
+			meta:     config.Metadata{"response_strategy": "failover"},
+			targets:  []Middleware{fail(0), ok("second", 0), ok("third", 0)},
+			wantBody: "second",
+		},
+		{
+			name:     "failover - dropped",
+			meta:     config.Metadata{"response_strategy": "failover"},
+			targets:  []Middleware{dropped, ok("second", 0)},
+			wantBody: "second",
+		},
+		{
+			name:    "first-success - all dropped",
+			meta:    config.Metadata{"response_strategy": "first-success"},
+			targets: []Middleware{dropped, dropped},
+			wantErr: true,
+		},
+		{
+			name:    "failover - all failed",
+			meta:    config.Metadata{"response_strategy": "failover"},
+			targets: []Middleware{fail(0), fail(0)},
+			wantErr: true,
+		},
+		{
+			name:     "all",
+			meta:     config.Metadata{"response_strategy": "all"},
+			targets:  []Middleware{ok(`{"a":1}`, 20*time.Millisecond), fail(0), ok("text", 0), &mockTarget{}, dropped},
+			wantBody: `[{"target":0,"executed":true,"body":{"a":1}},{"target":1,"executed":false,"error":"target-error"},{"target":2,"executed":true,"body":"text"},{"target":3,"executed":true},{"target":4,"executed":false,"error":"request dropped, filtered by script"}]`,
+		},
+		{
+			name:    "all - all failed",
+			meta:    config.Metadata{"response_strategy": "all"},
+			targets: []Middleware{fail(0), fail(0)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sg, err := NewScatterGatherMiddleware(tt.meta)
+			require.NoError(t, err)
+			require.True(t, sg.IsActive())
+			require.NoError(t, sg.Validate(len(tt.targets)))
+			start := time.Now()
+			resp, err := ScatterGather(sg, tt.targets).Do(context.Background(), "request")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantBody, string(resp.(*kubemq.QueryResponse).Body))
+			if tt.maxTime > 0 {
+				require.Less(t, time.Since(start), tt.maxTime)
+			}
+		})
+	}
+}
+
+func TestClient_ScatterGatherOptions(t *testing.T) {
+	sg, err := NewScatterGatherMiddleware(config.Metadata{})
+	require.NoError(t, err)
+	require.False(t, sg.IsActive())
+	_, err = NewScatterGatherMiddleware(config.Metadata{"response_strategy": "random"})
+	require.Error(t, err)
+	_, err = NewScatterGatherMiddleware(config.Metadata{"response_strategy": "quorum"})
+	require.Error(t, err)
+	sg, err = NewScatterGatherMiddleware(config.Metadata{"response_strategy": "quorum", "response_quorum": "3"})
+	require.NoError(t, err)
+	require.Error(t, sg.Validate(2))
+}
@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/metrics"
+)
+
+type Pipeline struct {
+	cfg            config.BindingConfig
+	exporter       *metrics.Exporter
+	log            *logger.Logger
+	scripts        map[int]*ScriptMiddleware
+	audits         map[int]*AuditMiddleware
+	loopPrevention bool
+}
+
+func NewPipeline(cfg config.BindingConfig, exporter *metrics.Exporter, log *logger.Logger) (*Pipeline, error) {
+	p := &Pipeline{
+		cfg:      cfg,
+		exporter: exporter,
+		log:      log,
+		scripts:  map[int]*ScriptMiddleware{},
+		audits:   map[int]*AuditMiddleware{},
+	}
+	for i, md := range cfg.Middlewares {
+		if md.Kind == "loop-prevention" {
+			p.loopPrevention = true
+		}
+		if md.Kind == "audit" {
+			am, err := NewAuditMiddleware(cfg.Name, md.Properties, log)
+			if err != nil {
+				_ = p.Close()
+				return nil, fmt.Errorf("error loading audit middleware, %w", err)
+			}
+			p.audits[i] = am
+			continue
+		}
+		if md.Kind != "script" {
+			continue
+		}
+		sm, err := NewScriptMiddleware(fmt.Sprintf("%s-%d", cfg.Name, i), md.Properties)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("error loading script middleware, %w", err)
+		}
+		p.scripts[i] = sm
+	}
+	if p.loopPrevention {
+		if err := validateLoopPrevention(cfg); err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// validateLoopPrevention checks that every loop-prevention stage is listed after a
+// provenance stage of the same targets. Stages listed later wrap the earlier ones,
+// so a loop-prevention stage listed first would see the local hop already stamped
+// and drop every message.
+func validateLoopPrevention(cfg config.BindingConfig) error {
+	hasProvenance := false
+	for _, md := range cfg.Middlewares {
+		if md.Kind == "provenance" {
+			hasProvenance = true
+		}
+	}
+	if !hasProvenance {
+		return fmt.Errorf("loop-prevention middleware requires a provenance middleware")
+	}
+	for i, md := range cfg.Middlewares {
+		if md.Kind != "loop-prevention" {
+			continue
+		}
+		for index := range cfg.Targets.Connections {
+			if !md.AppliesTo(index) {
+				continue
+			}
+			stamped := false
+			for _, prev := range cfg.Middlewares[:i] {
+				if prev.Kind == "provenance" && prev.AppliesTo(index) {
+					stamped = true
+					break
+				}
+			}
+			if !stamped {
+				return fmt.Errorf("loop-prevention middleware of target %d must be listed after a provenance middleware of the same target", index)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Pipeline) Build(target Middleware, index int) (Middleware, error) {
+	var list []MiddlewareFunc
+	for i, md := range p.cfg.Middlewares {
+		if !md.AppliesTo(index) {
+			continue
+		}
+		if sm, ok := p.scripts[i]; ok {
+			list = append(list, Script(sm, index))
+			continue
+		}
+		if am, ok := p.audits[i]; ok {
+			list = append(list, Audit(am, index, p.targetAddress(index)))
+			continue
+		}
+		switch md.Kind {
+		case "provenance":
+			pm, err := NewProvenanceMiddleware(p.cfg.Name, p.targetAddress(index), md.Properties)
+			if err != nil {
+				return nil, fmt.Errorf("error loading %s middleware, %w", md.Kind, err)
+			}
+			if p.loopPrevention {
+				pm.Require(LoopProvenanceFields...)
+			}
+			list = append(list, Provenance(pm))
+			continue
+		case "loop-prevention":
+			lm, err := NewLoopMiddleware(p.cfg, p.targetAddress(index), md.Properties, p.exporter, p.log)
+			if err != nil {
+				return nil, fmt.Errorf("error loading %s middleware, %w", md.Kind, err)
+			}
+			list = append(list, Loop(lm))
+			continue
+		}
+		mf, err := Init(md, p.cfg, p.exporter, p.log)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s middleware, %w", md.Kind, err)
+		}
+		if mf != nil {
+			list = append(list, mf)
+		}
+	}
+	return Chain(target, list...), nil
+}
+
+func (p *Pipeline) Close() error {
+	for _, am := range p.audits {
+		if err := am.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Pipeline) targetAddress(index int) string {
+	return p.cfg.Targets.Connections[index].ParseString("address", "")
+}
+
+func Init(md config.MiddlewareConfig, cfg config.BindingConfig, exporter *metrics.Exporter, log *logger.Logger) (MiddlewareFunc, error) {
+	meta := md.Properties
+	if meta == nil {
+		meta = config.NewMetadata()
+	}
+	switch md.Kind {
+	case "log":
+		lm, err := NewLogMiddleware(cfg.Name, meta)
+		if err != nil {
+			return nil, err
+		}
+		return Log(lm), nil
+	case "retry":
+		rm, err := NewRetryMiddleware(meta, log)
+		if err != nil {
+			return nil, err
+		}
+		return Retry(rm), nil
+	case "rate-limiter":
+		rl, err := NewRateLimitMiddleware(meta)
+		if err != nil {
+			return nil, err
+		}
+		return RateLimiter(rl), nil
+	case "metrics":
+		if exporter == nil {
+			return nil, nil
+		}
+		mm, err := NewMetricsMiddleware(cfg, exporter)
+		if err != nil {
+			return nil, err
+		}
+		return Metric(mm), nil
+	default:
+		return nil, fmt.Errorf("invalid middleware kind %s", md.Kind)
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+)
+
+type ProvenanceMiddleware struct {
+	binding string
+	target  string
+	opts    provenance.Options
+}
+
+func NewProvenanceMiddleware(binding, target string, meta config.Metadata) (*ProvenanceMiddleware, error) {
+	fields, err := provenance.ParseFields(meta.ParseStringList("provenance_tags"))
+	if err != nil {
+		return nil, err
+	}
+	return &ProvenanceMiddleware{
+		binding: binding,
+		target:  target,
+		opts: provenance.Options{
+			Prefix: meta.ParseString("provenance_prefix", provenance.DefaultPrefix),
+			Fields: fields,
+		},
+	}, nil
+}
+
+// Require adds fields that must be stamped regardless of the configured provenance tags.
+func (p *ProvenanceMiddleware) Require(fields ...string) {
+	for _, field := range fields {
+		p.opts.Fields[field] = true
+	}
+}
+
+func (p *ProvenanceMiddleware) Stamp(ctx context.Context, request interface{}) (interface{}, error) {
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
+	}
+	provenance.Stamp(msg, provenance.Hop{
+		Instance: provenance.InstanceID(),
+		Binding:  p.binding,
+		Source:   provenance.Source(ctx),
+		Target:   p.target,
+		Channel:  msg.Channel,
+	}, p.opts)
+	return msg.Apply(request)
+}
+
+func Provenance(p *ProvenanceMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			req, err := p.Stamp(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+			return df.Do(ctx, req)
+		})
+	}
+}
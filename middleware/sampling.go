@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"go.uber.org/atomic"
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+type SamplingMiddleware struct {
+	percent int
+	every   int
+	key     string
+	counter *atomic.Uint64
+}
+
+func NewSamplingMiddleware(meta config.Metadata) (*SamplingMiddleware, error) {
+	percent, err := meta.ParseIntWithRange("sample_percent", 100, 0, 100)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sample percent value, %w", err)
+	}
+	every, err := meta.ParseIntWithRange("sample_every", 0, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sample every value, %w", err)
+	}
+	key := meta.ParseString("sample_key", "")
+	if key != "" {
+		if err := message.ValidateKey(key); err != nil {
+			return nil, fmt.Errorf("invalid sample key value, %w", err)
+		}
+	}
+	return &SamplingMiddleware{
+		percent: percent,
+		every:   every,
+		key:     key,
+		counter: atomic.NewUint64(0),
+	}, nil
+}
+
+func (s *SamplingMiddleware) IsActive() bool {
+	return s.every > 0 || s.percent < 100
+}
+
+func (s *SamplingMiddleware) Sample(request interface{}) bool {
+	if s.every > 0 {
+		return (s.counter.Inc()-1)%uint64(s.every) == 0
+	}
+	if s.percent >= 100 {
+		return true
+	}
+	if s.percent <= 0 {
+		return false
+	}
+	if s.key == "" {
+		return rand.Intn(100) < s.percent
+	}
+	msg, err := message.From(request)
+	if err != nil {
+		return rand.Intn(100) < s.percent
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(msg.Key(s.key)))
+	return int(h.Sum32()%100) < s.percent
+}
+
+func Sampling(s *SamplingMiddleware) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			if !s.Sample(request) {
+				return &DroppedResponse{Reason: "sampled out"}, nil
+			}
+			return df.Do(ctx, request)
+		})
+	}
+}
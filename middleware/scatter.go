@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"strings"
+	"time"
+)
+
+const (
+	StrategyFirstSuccess = "first-success"
+	StrategyAll          = "all"
+	StrategyQuorum       = "quorum"
+	StrategyFailover     = "failover"
+)
+
+var strategyMap = map[string]string{
+	"":                   "",
+	StrategyFirstSuccess: StrategyFirstSuccess,
+	StrategyAll:          StrategyAll,
+	StrategyQuorum:       StrategyQuorum,
+	StrategyFailover:     StrategyFailover,
+}
+
+type ScatterGatherMiddleware struct {
+	strategy string
+	quorum   int
+}
+
+func NewScatterGatherMiddleware(meta config.Metadata) (*ScatterGatherMiddleware, error) {
+	sg := &ScatterGatherMiddleware{}
+	var err error
+	sg.strategy, err = meta.ParseStringMap("response_strategy", strategyMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response strategy value, %w", err)
+	}
+	if sg.strategy == StrategyQuorum {
+		sg.quorum, err = meta.ParseIntWithRange("response_quorum", 0, 1, 1024)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response quorum value, %w", err)
+		}
+	}
+	return sg, nil
+}
+
+// IsActive reports whether a response strategy is set. Without one, command and query sources
+// subscribe once per target and the server delivers each request to a single target.
+func (sg *ScatterGatherMiddleware) IsActive() bool {
+	return sg.strategy != ""
+}
+
+func (sg *ScatterGatherMiddleware) Validate(targets int) error {
+	if sg.strategy == StrategyQuorum && sg.quorum > targets {
+		return fmt.Errorf("response quorum %d exceeds %d targets", sg.quorum, targets)
+	}
+	return nil
+}
+
+type targetResult struct {
+	target   int
+	response interface{}
+	err      error
+}
+
+// GatherResult is the status of a target in the response of the all strategy.
+type GatherResult struct {
+	Target   int             `json:"target"`
+	Executed bool            `json:"executed"`
+	Error    string          `json:"error,omitempty"`
+	Metadata string          `json:"metadata,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+}
+
+// ScatterGather sends each request to the targets according to the response strategy and
+// returns a single response.
+func ScatterGather(sg *ScatterGatherMiddleware, targets []Middleware) Middleware {
+	return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		switch sg.strategy {
+		case StrategyFailover:
+			return sg.failover(ctx, targets, request)
+		case StrategyAll:
+			return sg.all(ctx, targets, request)
+		case StrategyQuorum:
+			return sg.wait(ctx, targets, request, sg.quorum)
+		default:
+			return sg.wait(ctx, targets, request, 1)
+		}
+	})
+}
+
+func scatter(ctx context.Context, targets []Middleware, request interface{}) <-chan targetResult {
+	results := make(chan targetResult, len(targets))
+	for i, target := range targets {
+		go func(i int, target Middleware) {
+			response, err := doTarget(ctx, target, request)
+			results <- targetResult{target: i, response: response, err: err}
+		}(i, target)
+	}
+	return results
+}
+
+// doTarget counts a request dropped by the target middlewares as a failed target.
+func doTarget(ctx context.Context, target Middleware, request interface{}) (interface{}, error) {
+	response, err := target.Do(ctx, request)
+	if dropped, ok := response.(*DroppedResponse); ok && err == nil {
+		return nil, dropped.Err()
+	}
+	return response, err
+}
+
+// wait fans out the request and returns the first successful response once required targets
+// succeeded. Targets still running after that are not waited for.
+func (sg *ScatterGatherMiddleware) wait(ctx context.Context, targets []Middleware, request interface{}, required int) (interface{}, error) {
+	results := scatter(ctx, targets, request)
+	var first interface{}
+	var errs []targetResult
+	succeeded := 0
+	for range targets {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, result)
+			if len(targets)-len(errs) < required {
+				return nil, gatherError(errs)
+			}
+			continue
+		}
+		if succeeded == 0 {
+			first = result.response
+		}
+		succeeded++
+		if succeeded == required {
+			return first, nil
+		}
+	}
+	return nil, gatherError(errs)
+}
+
+func (sg *ScatterGatherMiddleware) failover(ctx context.Context, targets []Middleware, request interface{}) (interface{}, error) {
+	var errs []targetResult
+	for i, target := range targets {
+		response, err := doTarget(ctx, target, request)
+		if err == nil {
+			return response, nil
+		}
+		errs = append(errs, targetResult{target: i, err: err})
+	}
+	return nil, gatherError(errs)
+}
+
+// all waits for every target and merges the responses into a JSON array with the status of each
+// target. It fails only when every target failed.
+func (sg *ScatterGatherMiddleware) all(ctx context.Context, targets []Middleware, request interface{}) (interface{}, error) {
+	results := scatter(ctx, targets, request)
+	list := make([]GatherResult, len(targets))
+	var errs []targetResult
+	for range targets {
+		result := <-results
+		list[result.target] = newGatherResult(result)
+		if result.err != nil {
+			errs = append(errs, result)
+		}
+	}
+	if len(errs) == len(targets) {
+		return nil, gatherError(errs)
+	}
+	body, err := logJson.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return &kubemq.QueryResponse{
+		Executed:   true,
+		ExecutedAt: time.Now(),
+		Body:       body,
+	}, nil
+}
+
+func newGatherResult(result targetResult) GatherResult {
+	r := GatherResult{
+		Target:   result.target,
+		Executed: result.err == nil,
+	}
+	if result.err != nil {
+		r.Error = result.err.Error()
+		return r
+	}
+	var body []byte
+	switch val := result.response.(type) {
+	case *kubemq.QueryResponse:
+		r.Metadata = val.Metadata
+		body = val.Body
+	case *kubemq.CommandResponse:
+		r.Executed = val.Executed
+		r.Error = val.Error
+	}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			r.Body = body
+		} else if data, err := logJson.Marshal(string(body)); err == nil {
+			r.Body = data
+		}
+	}
+	return r
+}
+
+func gatherError(errs []targetResult) error {
+	var list []string
+	for _, result := range errs {
+		list = append(list, fmt.Sprintf("target %d: %s", result.target, result.err.Error()))
+	}
+	return fmt.Errorf("no successful response, %s", strings.Join(list, "; "))
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+	"io/ioutil"
+	"math"
+	"time"
+)
+
+const (
+	defaultScriptFunction = "process"
+	defaultScriptTimeout  = 1000
+)
+
+type ScriptMiddleware struct {
+	name     string
+	fn       starlark.Callable
+	timeout  time.Duration
+	maxSteps uint64
+}
+
+func NewScriptMiddleware(name string, meta config.Metadata) (*ScriptMiddleware, error) {
+	src := meta.ParseString("script", "")
+	filename := meta.ParseString("script_file", "")
+	if src == "" && filename == "" {
+		return nil, fmt.Errorf("script or script_file must be set")
+	}
+	if src == "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading script file, %w", err)
+		}
+		src = string(data)
+	} else {
+		filename = name + ".star"
+	}
+	timeout, err := meta.ParseIntWithRange("script_timeout_milliseconds", defaultScriptTimeout, 1, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script timeout milliseconds value, %w", err)
+	}
+	maxSteps, err := meta.ParseIntWithRange("script_max_steps", 0, 0, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script max steps value, %w", err)
+	}
+	function := meta.ParseString("script_function", defaultScriptFunction)
+	thread := &starlark.Thread{Name: name}
+	globals, err := starlark.ExecFile(thread, filename, src, starlark.StringDict{
+		"json": json.Module,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error compiling script, %w", err)
+	}
+	fn, ok := globals[function].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("script function %s not found", function)
+	}
+	globals.Freeze()
+	return &ScriptMiddleware{
+		name:     name,
+		fn:       fn,
+		timeout:  time.Duration(timeout) * time.Millisecond,
+		maxSteps: uint64(maxSteps),
+	}, nil
+}
+
+// Process runs the script on the request and returns the request to forward to the target,
+// or false when the script dropped the message or routed it away from this target.
+func (s *ScriptMiddleware) Process(ctx context.Context, request interface{}, target int) (interface{}, bool, error) {
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, false, err
+	}
+	thread := &starlark.Thread{Name: s.name}
+	if s.maxSteps > 0 {
+		thread.SetMaxExecutionSteps(s.maxSteps)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-time.After(s.timeout):
+			thread.Cancel("script execution timeout")
+		case <-ctx.Done():
+			thread.Cancel("context canceled")
+		case <-done:
+		}
+	}()
+	result, err := starlark.Call(thread, s.fn, starlark.Tuple{toScriptMessage(msg)}, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("script error, %w", err)
+	}
+	switch val := result.(type) {
+	case starlark.NoneType:
+		return nil, false, nil
+	case *starlark.Dict:
+		targets, err := fromScriptMessage(val, msg)
+		if err != nil {
+			return nil, false, err
+		}
+		if targets != nil && !targets[target] {
+			return nil, false, nil
+		}
+		req, err := msg.Apply(request)
+		if err != nil {
+			return nil, false, err
+		}
+		return req, true, nil
+	default:
+		return nil, false, fmt.Errorf("script returned invalid type %s, expected dict or None", result.Type())
+	}
+}
+
+func toScriptMessage(msg *message.Message) *starlark.Dict {
+	tags := starlark.NewDict(len(msg.Tags))
+	for key, value := range msg.Tags {
+		_ = tags.SetKey(starlark.String(key), starlark.String(value))
+	}
+	dict := starlark.NewDict(5)
+	_ = dict.SetKey(starlark.String("id"), starlark.String(msg.ID))
+	_ = dict.SetKey(starlark.String("channel"), starlark.String(msg.Channel))
+	_ = dict.SetKey(starlark.String("metadata"), starlark.String(msg.Metadata))
+	_ = dict.SetKey(starlark.String("body"), starlark.String(msg.Body))
+	_ = dict.SetKey(starlark.String("tags"), tags)
+	return dict
+}
+
+func fromScriptMessage(dict *starlark.Dict, msg *message.Message) (map[int]bool, error) {
+	var err error
+	if msg.ID, err = scriptString(dict, "id", msg.ID); err != nil {
+		return nil, err
+	}
+	if msg.Channel, err = scriptString(dict, "channel", msg.Channel); err != nil {
+		return nil, err
+	}
+	if msg.Metadata, err = scriptString(dict, "metadata", msg.Metadata); err != nil {
+		return nil, err
+	}
+	body, err := scriptString(dict, "body", string(msg.Body))
+	if err != nil {
+		return nil, err
+	}
+	msg.Body = []byte(body)
+	if val, found, _ := dict.Get(starlark.String("tags")); found {
+		tagsDict, ok := val.(*starlark.Dict)
+		if !ok {
+			return nil, fmt.Errorf("script message tags must be a dict")
+		}
+		msg.Tags = map[string]string{}
+		for _, item := range tagsDict.Items() {
+			key, keyOk := starlark.AsString(item[0])
+			value, valueOk := starlark.AsString(item[1])
+			if !keyOk || !valueOk {
+				return nil, fmt.Errorf("script message tags must be strings")
+			}
+			msg.Tags[key] = value
+		}
+	}
+	val, found, _ := dict.Get(starlark.String("targets"))
+	if !found || val == starlark.None {
+		return nil, nil
+	}
+	list, ok := val.(*starlark.List)
+	if !ok {
+		return nil, fmt.Errorf("script message targets must be a list")
+	}
+	targets := map[int]bool{}
+	for i := 0; i < list.Len(); i++ {
+		index, err := starlark.AsInt32(list.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("script message targets must be a list of ints")
+		}
+		targets[index] = true
+	}
+	return targets, nil
+}
+
+func scriptString(dict *starlark.Dict, key, defaultValue string) (string, error) {
+	val, found, _ := dict.Get(starlark.String(key))
+	if !found {
+		return defaultValue, nil
+	}
+	str, ok := starlark.AsString(val)
+	if !ok {
+		return "", fmt.Errorf("script message %s must be a string", key)
+	}
+	return str, nil
+}
+
+func Script(s *ScriptMiddleware, target int) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			req, ok, err := s.Process(ctx, request, target)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return &DroppedResponse{Reason: "filtered by script"}, nil
+			}
+			return df.Do(ctx, req)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+)
+
+// Shadow mirrors every request to the shadow targets on the pool workers before calling the
+// primary target. Shadow results are only logged, and requests are dropped when the pool is full,
+// so shadow targets never slow down or fail the primary path.
+func Shadow(shadows []Middleware, p *pool.Pool, log *logger.Logger) MiddlewareFunc {
+	return func(df Middleware) Middleware {
+		return DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+			for _, shadow := range shadows {
+				shadow := shadow
+				ok := p.TrySubmit(func() {
+					_, err := shadow.Do(ctx, request)
+					if err != nil {
+						log.Errorf("error received from shadow target, %s", err.Error())
+					}
+				})
+				if !ok {
+					log.Debugf("shadow target pool is full, request dropped")
+				}
+			}
+			return df.Do(ctx, request)
+		})
+	}
+}
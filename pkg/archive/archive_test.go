@@ -0,0 +1,246 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecord(id, channel string) *Record {
+	return NewRecord(&message.Message{
+		ID:        id,
+		Channel:   channel,
+		Metadata:  "metadata",
+		Body:      []byte{0, 1, 2},
+		Tags:      map[string]string{"key": "value"},
+		Timestamp: time.Unix(1000, 0).UTC(),
+		Source:    message.SourceQueue,
+	})
+}
+
+func readAll(t *testing.T, path string) []*Record {
+	files, err := Files(path)
+	require.NoError(t, err)
+	r := NewReader(files)
+	defer r.Close()
+	var records []*Record
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func relFiles(t *testing.T, dir string) []string {
+	files, err := Files(dir)
+	require.NoError(t, err)
+	var list []string
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		require.NoError(t, err)
+		list = append(list, filepath.ToSlash(rel))
+	}
+	return list
+}
+
+func TestWriter_TimePartition(t *testing.T) {
+	for _, gzip := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzip %t", gzip), func(t *testing.T) {
+			dir := t.TempDir()
+			opts := WriterOptions{Dir: dir, Partition: PartitionTime, Interval: time.Hour, MaxFileSize: 400, Gzip: gzip}
+			w, err := NewWriter(opts)
+			require.NoError(t, err)
+			now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+			w.now = func() time.Time { return now }
+			for i := 0; i < 6; i++ {
+				if i == 4 {
+					now = now.Add(time.Hour)
+				}
+				require.NoError(t, w.Write(newRecord(fmt.Sprintf("%d", i), "orders")))
+			}
+			require.NoError(t, w.Close())
+
+			ext := ".jsonl"
+			if gzip {
+				ext += ".gz"
+			}
+			files := relFiles(t, dir)
+			require.Contains(t, files, "20240101T100000Z-000001"+ext)
+			require.Contains(t, files, "20240101T110000Z-000001"+ext)
+			if !gzip {
+				// each record is about 190 bytes, so a file holds two of them
+				require.Equal(t, []string{
+					"20240101T100000Z-000001" + ext,
+					"20240101T100000Z-000002" + ext,
+					"20240101T110000Z-000001" + ext,
+				}, files)
+			}
+			records := readAll(t, dir)
+			require.Len(t, records, 6)
+			for i, record := range records {
+				require.Equal(t, fmt.Sprintf("%d", i), record.ID)
+				require.Equal(t, []byte{0, 1, 2}, record.Body)
+				require.Equal(t, "value", record.Tags["key"])
+				require.Equal(t, message.SourceQueue, record.Source)
+				require.True(t, record.Timestamp.Equal(time.Unix(1000, 0)))
+			}
+		})
+	}
+}
+
+func TestWriter_ChannelPartition(t *testing.T) {
+	dir := t.TempDir()
+	opts := WriterOptions{Dir: dir, Partition: PartitionChannel, MaxFileSize: 1024 * 1024}
+	w, err := NewWriter(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(newRecord("1", "orders")))
+	require.NoError(t, w.Write(newRecord("2", "events/audit")))
+	require.NoError(t, w.Write(newRecord("3", "orders")))
+	require.NoError(t, w.Close())
+	require.Equal(t, []string{
+		"events_audit/messages-000001.jsonl",
+		"orders/messages-000001.jsonl",
+	}, relFiles(t, dir))
+	records := readAll(t, filepath.Join(dir, "orders"))
+	require.Len(t, records, 2)
+	require.Equal(t, "3", records[1].ID)
+}
+
+func TestWriter_Restart(t *testing.T) {
+	dir := t.TempDir()
+	opts := WriterOptions{Dir: dir, Partition: PartitionChannel, MaxFileSize: 1024 * 1024, Gzip: true}
+	for i := 0; i < 2; i++ {
+		w, err := NewWriter(opts)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(newRecord(fmt.Sprintf("%d", i), "orders")))
+		require.NoError(t, w.Close())
+	}
+	require.Equal(t, []string{"orders/messages-000001.jsonl.gz", "orders/messages-000002.jsonl.gz"}, relFiles(t, dir))
+	require.Len(t, readAll(t, dir), 2)
+
+	opts.Gzip = false
+	for i := 2; i < 4; i++ {
+		w, err := NewWriter(opts)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(newRecord(fmt.Sprintf("%d", i), "orders")))
+		require.NoError(t, w.Close())
+	}
+	require.Equal(t, []string{
+		"orders/messages-000001.jsonl.gz",
+		"orders/messages-000002.jsonl.gz",
+		"orders/messages-000003.jsonl",
+	}, relFiles(t, dir))
+	require.Len(t, readAll(t, dir), 4)
+}
+
+func TestWriter_RestartAfterCrash(t *testing.T) {
+	dir := t.TempDir()
+	opts := WriterOptions{Dir: dir, Partition: PartitionChannel, MaxFileSize: 1024 * 1024, Gzip: true}
+	// the first writer is never closed, its file has no gzip footer
+	crashed, err := NewWriter(opts)
+	require.NoError(t, err)
+	require.NoError(t, crashed.Write(newRecord("1", "orders")))
+
+	w, err := NewWriter(opts)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(newRecord("2", "orders")))
+	require.NoError(t, w.Write(newRecord("3", "orders")))
+	require.NoError(t, w.Close())
+	require.Equal(t, []string{"orders/messages-000001.jsonl.gz", "orders/messages-000002.jsonl.gz"}, relFiles(t, dir))
+	var ids []string
+	for _, record := range readAll(t, dir) {
+		ids = append(ids, record.ID)
+	}
+	require.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestReader_UnclosedGzip(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(WriterOptions{Dir: dir, Partition: PartitionChannel, MaxFileSize: 1024 * 1024, Gzip: true})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(newRecord("1", "orders")))
+	require.NoError(t, w.Write(newRecord("2", "orders")))
+	require.Len(t, readAll(t, dir), 2)
+	require.NoError(t, w.Close())
+}
+
+func TestReader_Errors(t *testing.T) {
+	dir := t.TempDir()
+	line, err := json.Marshal(newRecord("1", "orders"))
+	require.NoError(t, err)
+	content := string(line) + "\nnot json\n\n" + string(line)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.jsonl"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.jsonl.gz"), []byte("not gzip"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.jsonl"), append(line, '\n'), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.txt"), []byte("ignored"), 0644))
+
+	files, err := Files(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	r := NewReader(files)
+	var ok, failed int
+	for {
+		_, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			failed++
+			continue
+		}
+		ok++
+	}
+	require.Equal(t, 3, ok)
+	require.Equal(t, 2, failed)
+
+	files, err = Files(filepath.Join(dir, "*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestMerger(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(WriterOptions{Dir: dir, Partition: PartitionChannel, MaxFileSize: 1024 * 1024})
+	require.NoError(t, err)
+	start := time.Now()
+	for i, channel := range []string{"orders", "alerts", "orders", "alerts", "orders"} {
+		record := newRecord(fmt.Sprintf("%d", i+1), channel)
+		record.Timestamp = start.Add(time.Duration(i) * time.Second)
+		require.NoError(t, w.Write(record))
+	}
+	require.NoError(t, w.Close())
+	files, err := Files(dir)
+	require.NoError(t, err)
+	orders := filepath.Join(dir, "orders", "messages-000001.jsonl")
+
+	read := func(positions map[string]uint64) ([]string, map[string]uint64) {
+		m := NewMerger(files, positions)
+		defer m.Close()
+		var ids []string
+		last := map[string]uint64{}
+		for {
+			record, position, err := m.Next()
+			if err == io.EOF {
+				return ids, last
+			}
+			require.NoError(t, err)
+			ids = append(ids, record.ID)
+			last[position.File] = position.Line
+		}
+	}
+	ids, last := read(nil)
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+	require.Equal(t, uint64(3), last[orders])
+
+	ids, _ = read(map[string]uint64{orders: 2})
+	require.Equal(t, []string{"2", "4", "5"}, ids)
+}
@@ -0,0 +1,249 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Files returns the archive files of path, sorted by name. Path is a file, a dir searched
+// recursively for .jsonl and .jsonl.gz files, or a glob pattern.
+func Files(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err == nil && !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	if err == nil {
+		err = filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && isArchiveFile(name) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err == nil && !info.IsDir() {
+				files = append(files, match)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isArchiveFile(name string) bool {
+	return strings.HasSuffix(name, fileExtension) || strings.HasSuffix(name, fileExtension+gzipExtension)
+}
+
+// Position is the file and line of a record, lines are numbered from 1.
+type Position struct {
+	File string
+	Line uint64
+}
+
+// Reader reads the records of files one after another. Gzip files are detected by their
+// extension.
+type Reader struct {
+	files  []string
+	index  int
+	fd     *os.File
+	reader *bufio.Reader
+	line   uint64
+	skip   map[string]uint64
+}
+
+func NewReader(files []string) *Reader {
+	return &Reader{
+		files: files,
+		index: -1,
+	}
+}
+
+// NewReaderAt returns a reader skipping the lines of each file up to the line set in positions.
+func NewReaderAt(files []string, positions map[string]uint64) *Reader {
+	r := NewReader(files)
+	r.skip = positions
+	return r
+}
+
+// File returns the name of the file being read.
+func (r *Reader) File() string {
+	if r.index < 0 || r.index >= len(r.files) {
+		return ""
+	}
+	return r.files[r.index]
+}
+
+// Position returns the position of the line last read.
+func (r *Reader) Position() Position {
+	return Position{
+		File: r.File(),
+		Line: r.line,
+	}
+}
+
+// Next returns the next record, or io.EOF after the last file. Other errors are returned for a
+// line that cannot be decoded or a file that cannot be read; the next call continues with the
+// next line or file.
+func (r *Reader) Next() (*Record, error) {
+	for {
+		if r.reader == nil {
+			if r.index+1 >= len(r.files) {
+				return nil, io.EOF
+			}
+			r.index++
+			r.line = 0
+			if err := r.open(r.files[r.index]); err != nil {
+				return nil, err
+			}
+		}
+		line, err := r.reader.ReadBytes('\n')
+		if len(line) > 0 {
+			r.line++
+		}
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			r.closeFile()
+			return nil, fmt.Errorf("error reading archive file %s, %w", r.File(), err)
+		}
+		if err != nil {
+			// a gzip file still being written ends without a gzip footer
+			r.closeFile()
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || r.line <= r.skip[r.File()] {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("error decoding record in archive file %s, %w", r.File(), err)
+		}
+		return record, nil
+	}
+}
+
+func (r *Reader) open(name string) error {
+	fd, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("error opening archive file, %w", err)
+	}
+	var reader io.Reader = fd
+	if strings.HasSuffix(name, gzipExtension) {
+		gz, err := gzip.NewReader(bufio.NewReader(fd))
+		if err != nil {
+			_ = fd.Close()
+			return fmt.Errorf("error opening archive file %s, %w", name, err)
+		}
+		reader = gz
+	}
+	r.fd = fd
+	r.reader = bufio.NewReader(reader)
+	return nil
+}
+
+func (r *Reader) closeFile() {
+	if r.fd != nil {
+		_ = r.fd.Close()
+	}
+	r.fd = nil
+	r.reader = nil
+}
+
+func (r *Reader) Close() error {
+	r.closeFile()
+	r.index = len(r.files)
+	return nil
+}
+
+// Merger reads the files of each dir with its own reader and returns the records of all dirs
+// ordered by timestamp, so channel partitions are replayed in the order they were recorded.
+type Merger struct {
+	readers []*Reader
+	heads   []*head
+}
+
+type head struct {
+	record   *Record
+	position Position
+	err      error
+}
+
+// NewMerger returns a merger of files, skipping the lines of each file up to the line set in
+// positions.
+func NewMerger(files []string, positions map[string]uint64) *Merger {
+	var dirs []string
+	groups := map[string][]string{}
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if _, ok := groups[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		groups[dir] = append(groups[dir], file)
+	}
+	m := &Merger{}
+	for _, dir := range dirs {
+		m.readers = append(m.readers, NewReaderAt(groups[dir], positions))
+		m.heads = append(m.heads, nil)
+	}
+	return m
+}
+
+// Next returns the next record and its position, or io.EOF after the last record. Like the
+// reader, other errors are returned for a line or file that cannot be read.
+func (m *Merger) Next() (*Record, Position, error) {
+	next := -1
+	for i, r := range m.readers {
+		if r == nil {
+			continue
+		}
+		if m.heads[i] == nil {
+			record, err := r.Next()
+			if err == io.EOF {
+				m.readers[i] = nil
+				continue
+			}
+			m.heads[i] = &head{record: record, position: r.Position(), err: err}
+		}
+		h := m.heads[i]
+		if h.err != nil {
+			next = i
+			break
+		}
+		if next < 0 || h.record.Timestamp.Before(m.heads[next].record.Timestamp) {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, Position{}, io.EOF
+	}
+	h := m.heads[next]
+	m.heads[next] = nil
+	return h.record, h.position, h.err
+}
+
+func (m *Merger) Close() error {
+	for _, r := range m.readers {
+		if r != nil {
+			_ = r.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package archive
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Record is a message as stored in an archive file, one json object per line. The body is base64
+// encoded, so binary bodies are kept as is.
+type Record struct {
+	ID         string            `json:"id"`
+	Channel    string            `json:"channel"`
+	Metadata   string            `json:"metadata,omitempty"`
+	Body       []byte            `json:"body"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Source     string            `json:"source,omitempty"`
+	RecordedAt time.Time         `json:"recorded_at"`
+}
+
+func NewRecord(msg *message.Message) *Record {
+	return &Record{
+		ID:         msg.ID,
+		Channel:    msg.Channel,
+		Metadata:   msg.Metadata,
+		Body:       msg.Body,
+		Tags:       msg.Tags,
+		Timestamp:  msg.Timestamp,
+		Source:     msg.Source,
+		RecordedAt: time.Now().UTC(),
+	}
+}
+
+// Message returns the record as a message. Its timestamp is the timestamp of the original message.
+func (r *Record) Message() *message.Message {
+	msg := &message.Message{
+		ID:        r.ID,
+		Channel:   r.Channel,
+		Metadata:  r.Metadata,
+		Body:      r.Body,
+		Tags:      map[string]string{},
+		Timestamp: r.Timestamp,
+	}
+	for key, value := range r.Tags {
+		msg.Tags[key] = value
+	}
+	return msg
+}
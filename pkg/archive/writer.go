@@ -0,0 +1,240 @@
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PartitionTime    = "time"
+	PartitionChannel = "channel"
+
+	fileExtension = ".jsonl"
+	gzipExtension = ".gz"
+	channelPrefix = "messages"
+	timeLayout    = "20060102T150405Z"
+)
+
+type WriterOptions struct {
+	Dir       string
+	Partition string
+	// Interval is the length of a time partition.
+	Interval    time.Duration
+	MaxFileSize int64
+	Gzip        bool
+}
+
+// Writer appends records to the files of their partition. A file is rotated when it reaches the
+// maximum size, the next file of the partition having the next index, so sorting the file names
+// sorts the records of a partition in the order they were written.
+type Writer struct {
+	opts  WriterOptions
+	mu    sync.Mutex
+	files map[string]*file
+	now   func() time.Time
+}
+
+func NewWriter(opts WriterOptions) (*Writer, error) {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating archive dir, %w", err)
+	}
+	return &Writer{
+		opts:  opts,
+		files: map[string]*file{},
+		now:   time.Now,
+	}, nil
+}
+
+func (w *Writer) Write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding record, %w", err)
+	}
+	line = append(line, '\n')
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	dir, prefix := w.partition(record)
+	key := filepath.Join(dir, prefix)
+	f, ok := w.files[key]
+	if ok && f.size > 0 && f.size+int64(len(line)) > w.opts.MaxFileSize {
+		if err := f.close(); err != nil {
+			return err
+		}
+		f, err = w.openFile(dir, prefix, f.index+1)
+		if err != nil {
+			return err
+		}
+		w.files[key] = f
+	}
+	if !ok {
+		if w.opts.Partition == PartitionTime {
+			if err := w.closeAll(); err != nil {
+				return err
+			}
+		}
+		f, err = w.openLast(dir, prefix)
+		if err != nil {
+			return err
+		}
+		w.files[key] = f
+	}
+	return f.write(line)
+}
+
+// partition returns the dir and the file name prefix of the record partition.
+func (w *Writer) partition(record *Record) (string, string) {
+	if w.opts.Partition == PartitionChannel {
+		return filepath.Join(w.opts.Dir, safeName(record.Channel)), channelPrefix
+	}
+	return w.opts.Dir, w.now().UTC().Truncate(w.opts.Interval).Format(timeLayout)
+}
+
+// openLast opens the last file of a partition to append to it, so a restarted writer continues
+// the existing files. Gzip files are never appended to: a file left by a process that died
+// without closing it ends with a truncated member, which stops the reading of the file.
+func (w *Writer) openLast(dir, prefix string) (*file, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating archive dir, %w", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"-*"+fileExtension+"*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	index := 1
+	if len(matches) > 0 {
+		last := matches[len(matches)-1]
+		index = fileIndex(last, prefix)
+		info, err := os.Stat(last)
+		if err != nil {
+			return nil, err
+		}
+		if w.opts.Gzip || last != w.fileName(dir, prefix, index) || info.Size() >= w.opts.MaxFileSize {
+			index++
+		}
+	}
+	return w.openFile(dir, prefix, index)
+}
+
+func (w *Writer) openFile(dir, prefix string, index int) (*file, error) {
+	name := w.fileName(dir, prefix, index)
+	fd, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening archive file, %w", err)
+	}
+	info, err := fd.Stat()
+	if err != nil {
+		_ = fd.Close()
+		return nil, err
+	}
+	f := &file{
+		fd:    fd,
+		index: index,
+		size:  info.Size(),
+	}
+	f.writer = &countingWriter{w: fd, count: &f.size}
+	if w.opts.Gzip {
+		f.gzip = gzip.NewWriter(f.writer)
+	}
+	return f, nil
+}
+
+func (w *Writer) fileName(dir, prefix string, index int) string {
+	name := fmt.Sprintf("%s-%06d%s", prefix, index, fileExtension)
+	if w.opts.Gzip {
+		name += gzipExtension
+	}
+	return filepath.Join(dir, name)
+}
+
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closeAll()
+}
+
+func (w *Writer) closeAll() error {
+	var firstErr error
+	for key, f := range w.files {
+		if err := f.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(w.files, key)
+	}
+	return firstErr
+}
+
+func fileIndex(name, prefix string) int {
+	base := strings.TrimPrefix(filepath.Base(name), prefix+"-")
+	base = strings.TrimSuffix(strings.TrimSuffix(base, gzipExtension), fileExtension)
+	index, err := strconv.Atoi(base)
+	if err != nil {
+		return 0
+	}
+	return index
+}
+
+// safeName returns a channel name usable as a dir name.
+func safeName(channel string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, channel)
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+type file struct {
+	fd     *os.File
+	writer io.Writer
+	gzip   *gzip.Writer
+	index  int
+	size   int64
+}
+
+// write appends the line. Gzip files are flushed after every line, so the written records can
+// be read before the file is closed.
+func (f *file) write(line []byte) error {
+	if f.gzip == nil {
+		_, err := f.writer.Write(line)
+		return err
+	}
+	if _, err := f.gzip.Write(line); err != nil {
+		return err
+	}
+	return f.gzip.Flush()
+}
+
+func (f *file) close() error {
+	if f.gzip != nil {
+		if err := f.gzip.Close(); err != nil {
+			_ = f.fd.Close()
+			return err
+		}
+	}
+	return f.fd.Close()
+}
+
+type countingWriter struct {
+	w     io.Writer
+	count *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+type Stats struct {
+	Name     string `json:"name"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// Cache counts the cache hits and misses of a target and keeps an optional local LRU cache of
+// its responses. A cache with zero capacity has no local entries and only counts.
+type Cache struct {
+	name     string
+	capacity int
+	ttl      time.Duration
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List
+	hits     *atomic.Uint64
+	misses   *atomic.Uint64
+	now      func() time.Time
+}
+
+func New(name string, capacity int, ttl time.Duration) *Cache {
+	return &Cache{
+		name:     name,
+		capacity: capacity,
+		ttl:      ttl,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+		hits:     atomic.NewUint64(0),
+		misses:   atomic.NewUint64(0),
+		now:      time.Now,
+	}
+}
+
+// Get returns the local entry of key if it did not expire, and marks it as recently used.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	if c.capacity == 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores the local entry of key, evicting the least recently used entry when the cache is full.
+func (c *Cache) Set(key string, value interface{}) {
+	if c.capacity == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+}
+
+func (c *Cache) Hit() {
+	c.hits.Inc()
+}
+
+func (c *Cache) Miss() {
+	c.misses.Inc()
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+	return Stats{
+		Name:     c.name,
+		Size:     size,
+		Capacity: c.capacity,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+	}
+}
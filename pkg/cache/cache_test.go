@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_LRU(t *testing.T) {
+	c := New("cache", 2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	val, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+	c.Set("c", 3)
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	_, ok = c.Get("a")
+	require.True(t, ok)
+	_, ok = c.Get("c")
+	require.True(t, ok)
+	c.Set("a", 10)
+	val, _ = c.Get("a")
+	require.Equal(t, 10, val)
+	require.Equal(t, 2, c.Stats().Size)
+}
+
+func TestCache_TTL(t *testing.T) {
+	now := time.Now()
+	c := New("cache", 10, time.Second)
+	c.now = func() time.Time { return now }
+	c.Set("a", 1)
+	now = now.Add(999 * time.Millisecond)
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	now = now.Add(time.Millisecond)
+	_, ok = c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Stats().Size)
+}
+
+func TestCache_NoLocal(t *testing.T) {
+	c := New("cache", 0, time.Minute)
+	c.Set("a", 1)
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	c.Hit()
+	c.Miss()
+	c.Miss()
+	require.Equal(t, Stats{Name: "cache", Hits: 1, Misses: 2}, c.Stats())
+}
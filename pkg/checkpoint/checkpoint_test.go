@@ -0,0 +1,53 @@
+package checkpoint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints", "events-store.json")
+	s, err := Open(path)
+	require.NoError(t, err)
+	key := Key("localhost:50000", "events-store", "g1")
+	_, ok := s.Get(key)
+	require.False(t, ok)
+	require.NoError(t, s.Set(Checkpoint{Key: key, Channel: "events-store", Group: "g1", Sequence: 10}))
+
+	same, err := Open(path)
+	require.NoError(t, err)
+	require.Same(t, s, same)
+
+	stores = map[string]*Store{}
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	cp, ok := reopened.Get(key)
+	require.True(t, ok)
+	require.EqualValues(t, 10, cp.Sequence)
+	require.False(t, cp.UpdatedAt.IsZero())
+	require.Len(t, reopened.List(), 1)
+
+	require.NoError(t, reopened.Delete(key))
+	stores = map[string]*Store{}
+	reopened, err = Open(path)
+	require.NoError(t, err)
+	require.Empty(t, reopened.List())
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker(10)
+	require.EqualValues(t, 10, tr.Sequence())
+	tr.Received(11)
+	tr.Received(12)
+	tr.Received(13)
+	tr.Done(12, true)
+	require.EqualValues(t, 10, tr.Sequence())
+	tr.Done(11, true)
+	require.EqualValues(t, 12, tr.Sequence())
+	tr.Done(13, false)
+	tr.Received(14)
+	tr.Done(14, true)
+	require.EqualValues(t, 12, tr.Sequence())
+}
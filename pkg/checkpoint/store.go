@@ -0,0 +1,127 @@
+package checkpoint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Checkpoint is the position of a source. Sources reading files keep the last line read per file
+// in Files.
+type Checkpoint struct {
+	Key       string            `json:"key"`
+	Address   string            `json:"address"`
+	Channel   string            `json:"channel"`
+	Group     string            `json:"group"`
+	Sequence  uint64            `json:"sequence"`
+	Files     map[string]uint64 `json:"files,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func Key(address, channel, group string) string {
+	return fmt.Sprintf("%s/%s/%s", address, channel, group)
+}
+
+// Store keeps checkpoints in a JSON file, rewriting the whole file on every change.
+type Store struct {
+	sync.Mutex
+	path        string
+	checkpoints map[string]Checkpoint
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = map[string]*Store{}
+)
+
+// Open returns the store of the file at path. Sources sharing a file share the same store, so
+// bindings can be reloaded without losing or overwriting each other's checkpoints.
+func Open(path string) (*Store, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if s, ok := stores[abs]; ok {
+		return s, nil
+	}
+	s := &Store{
+		path:        abs,
+		checkpoints: map[string]Checkpoint{},
+	}
+	data, err := ioutil.ReadFile(abs)
+	switch {
+	case err == nil:
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &s.checkpoints); err != nil {
+				return nil, fmt.Errorf("error reading checkpoint file %s, %w", abs, err)
+			}
+		}
+	case os.IsNotExist(err):
+	default:
+		return nil, err
+	}
+	stores[abs] = s
+	return s, nil
+}
+
+func (s *Store) Get(key string) (Checkpoint, bool) {
+	s.Lock()
+	defer s.Unlock()
+	cp, ok := s.checkpoints[key]
+	return cp, ok
+}
+
+func (s *Store) Set(cp Checkpoint) error {
+	s.Lock()
+	defer s.Unlock()
+	if cp.UpdatedAt.IsZero() {
+		cp.UpdatedAt = time.Now().UTC()
+	}
+	s.checkpoints[cp.Key] = cp
+	return s.save()
+}
+
+func (s *Store) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.checkpoints, key)
+	return s.save()
+}
+
+func (s *Store) List() []Checkpoint {
+	s.Lock()
+	defer s.Unlock()
+	var list []Checkpoint
+	for _, cp := range s.checkpoints {
+		list = append(list, cp)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.checkpoints, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
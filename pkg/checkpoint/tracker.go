@@ -0,0 +1,60 @@
+package checkpoint
+
+import (
+	"sync"
+)
+
+// Tracker computes the sequence up to which every received event was forwarded successfully.
+// Events are processed concurrently, so the checkpoint stays below the oldest event still in
+// flight. A failed event is never released and holds the checkpoint until the source restarts
+// and receives it again.
+type Tracker struct {
+	sync.Mutex
+	inFlight map[uint64]int
+	maxDone  uint64
+	last     uint64
+}
+
+func NewTracker(sequence uint64) *Tracker {
+	return &Tracker{
+		inFlight: map[uint64]int{},
+		maxDone:  sequence,
+		last:     sequence,
+	}
+}
+
+func (t *Tracker) Received(sequence uint64) {
+	t.Lock()
+	defer t.Unlock()
+	t.inFlight[sequence]++
+}
+
+func (t *Tracker) Done(sequence uint64, ok bool) {
+	t.Lock()
+	defer t.Unlock()
+	if !ok {
+		return
+	}
+	t.inFlight[sequence]--
+	if t.inFlight[sequence] <= 0 {
+		delete(t.inFlight, sequence)
+	}
+	if sequence > t.maxDone {
+		t.maxDone = sequence
+	}
+}
+
+func (t *Tracker) Sequence() uint64 {
+	t.Lock()
+	defer t.Unlock()
+	sequence := t.maxDone
+	for inFlight := range t.inFlight {
+		if inFlight <= sequence {
+			sequence = inFlight - 1
+		}
+	}
+	if sequence > t.last {
+		t.last = sequence
+	}
+	return t.last
+}
@@ -0,0 +1,377 @@
+package connpool
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	kindClient = "client"
+	kindQueues = "queues"
+
+	pingTimeout = 3 * time.Second
+)
+
+// Options identifies a shared connection. Sources and targets with equal options use the same
+// client. Connections without a client id share the default client of the manager per address,
+// auth token and TLS options.
+type Options struct {
+	Host              string
+	Port              int
+	ClientId          string
+	AuthToken         string
+	TLS               tlsconfig.Options
+	AutoReconnect     bool
+	ReconnectInterval time.Duration
+	MaxReconnects     int
+	// Instance separates connections with equal options, such as the parallel connections of a
+	// source. Connections without a client id get the instance as a suffix of the default client id.
+	Instance int
+	// Exclusive opts out of sharing, the connection gets a client of its own.
+	Exclusive bool
+}
+
+func (o Options) address() string {
+	return net.JoinHostPort(o.Host, strconv.Itoa(o.Port))
+}
+
+type conn interface {
+	value() interface{}
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+type clientConn struct {
+	*kubemq.Client
+}
+
+func (c clientConn) value() interface{} {
+	return c.Client
+}
+
+func (c clientConn) Ping(ctx context.Context) error {
+	_, err := c.Client.Ping(ctx)
+	return err
+}
+
+type queuesConn struct {
+	*queues_stream.QueuesStreamClient
+}
+
+func (c queuesConn) value() interface{} {
+	return c.QueuesStreamClient
+}
+
+// Ping checks the connection with a queues info request, the stream client has no ping call.
+func (c queuesConn) Ping(ctx context.Context) error {
+	_, err := c.QueuesInfo(ctx, "")
+	return err
+}
+
+type key struct {
+	kind      string
+	opts      Options
+	exclusive uint64
+}
+
+type entry struct {
+	key      key
+	clientId string
+	ready    chan struct{}
+	err      error
+	refs     int
+	conn     conn
+	cancel   context.CancelFunc
+}
+
+type dialFunc func(ctx context.Context, kind string, host string, port int, opts Options, log *logger.Logger) (conn, error)
+
+// Manager shares clients between the bindings of the process. Each client is reference counted
+// and closed when the last source or target using it releases it.
+type Manager struct {
+	mu        sync.Mutex
+	log       *logger.Logger
+	dial      dialFunc
+	clientId  string
+	exclusive uint64
+	entries   map[key]*entry
+	values    map[interface{}]*entry
+}
+
+func NewManager() *Manager {
+	return newManager(dial)
+}
+
+func newManager(dial dialFunc) *Manager {
+	return &Manager{
+		log:      logger.NewLogger("connpool"),
+		dial:     dial,
+		clientId: "kubemq-bridges-" + uuid.New().String(),
+		entries:  map[key]*entry{},
+		values:   map[interface{}]*entry{},
+	}
+}
+
+var defaultManager = NewManager()
+
+// Client returns a shared client of the default manager.
+func Client(ctx context.Context, opts Options) (*kubemq.Client, error) {
+	return defaultManager.Client(ctx, opts)
+}
+
+// QueuesClient returns a shared queues stream client of the default manager.
+func QueuesClient(ctx context.Context, opts Options) (*queues_stream.QueuesStreamClient, error) {
+	return defaultManager.QueuesClient(ctx, opts)
+}
+
+// Release releases a client returned by Client or QueuesClient of the default manager.
+func Release(client interface{}) error {
+	return defaultManager.Release(client)
+}
+
+// Status returns the connections of the default manager per address.
+func Status(ctx context.Context) []*AddressStatus {
+	return defaultManager.Status(ctx)
+}
+
+func (m *Manager) Client(ctx context.Context, opts Options) (*kubemq.Client, error) {
+	e, err := m.acquire(ctx, kindClient, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.conn.value().(*kubemq.Client), nil
+}
+
+func (m *Manager) QueuesClient(ctx context.Context, opts Options) (*queues_stream.QueuesStreamClient, error) {
+	e, err := m.acquire(ctx, kindQueues, opts)
+	if err != nil {
+		return nil, err
+	}
+	return e.conn.value().(*queues_stream.QueuesStreamClient), nil
+}
+
+func (m *Manager) acquire(ctx context.Context, kind string, opts Options) (*entry, error) {
+	k := key{kind: kind, opts: opts}
+	m.mu.Lock()
+	if opts.Exclusive {
+		m.exclusive++
+		k.exclusive = m.exclusive
+	}
+	e, ok := m.entries[k]
+	if ok {
+		e.refs++
+		m.mu.Unlock()
+		select {
+		case <-e.ready:
+		case <-ctx.Done():
+			m.release(e)
+			return nil, ctx.Err()
+		}
+		if e.err != nil {
+			return nil, e.err
+		}
+		return e, nil
+	}
+	e = &entry{
+		key:      k,
+		clientId: m.resolveClientId(opts),
+		ready:    make(chan struct{}),
+		refs:     1,
+	}
+	m.entries[k] = e
+	m.mu.Unlock()
+
+	err := m.open(e)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		e.err = err
+		delete(m.entries, k)
+	} else {
+		m.values[e.conn.value()] = e
+	}
+	close(e.ready)
+	return e, err
+}
+
+// open connects a new entry. The client is not bound to the context of the binding which
+// created it, as later bindings keep using it.
+func (m *Manager) open(e *entry) error {
+	opts := e.key.opts
+	opts.ClientId = e.clientId
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := m.dial(ctx, e.key.kind, opts.Host, opts.Port, opts, m.log)
+	if err != nil {
+		cancel()
+		return err
+	}
+	e.conn = c
+	e.cancel = cancel
+	return nil
+}
+
+// resolveClientId returns the client id of a connection. Exclusive connections without a client id
+// get a random one, shared connections without a client id get the default client id.
+func (m *Manager) resolveClientId(opts Options) string {
+	switch {
+	case opts.ClientId != "":
+		return opts.ClientId
+	case opts.Exclusive:
+		return uuid.New().String()
+	case opts.Instance > 0:
+		return fmt.Sprintf("%s-%d", m.clientId, opts.Instance)
+	default:
+		return m.clientId
+	}
+}
+
+func (m *Manager) Release(client interface{}) error {
+	m.mu.Lock()
+	e, ok := m.values[client]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("connection not found")
+	}
+	return m.release(e)
+}
+
+func (m *Manager) release(e *entry) error {
+	m.mu.Lock()
+	e.refs--
+	if e.refs > 0 || e.conn == nil {
+		m.mu.Unlock()
+		return nil
+	}
+	delete(m.entries, e.key)
+	delete(m.values, e.conn.value())
+	m.mu.Unlock()
+	err := e.conn.Close()
+	e.cancel()
+	return err
+}
+
+// AddressStatus reports the shared connections to an address.
+type AddressStatus struct {
+	Address     string   `json:"address"`
+	Connections int      `json:"connections"`
+	References  int      `json:"references"`
+	Healthy     bool     `json:"healthy"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// Status pings every connection and returns the connection and reference counts and health per
+// address.
+func (m *Manager) Status(ctx context.Context) []*AddressStatus {
+	type snapshot struct {
+		opts     Options
+		clientId string
+		refs     int
+		conn     conn
+		err      error
+	}
+	m.mu.Lock()
+	var list []*snapshot
+	for _, e := range m.entries {
+		if e.conn != nil {
+			list = append(list, &snapshot{opts: e.key.opts, clientId: e.clientId, refs: e.refs, conn: e.conn})
+		}
+	}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	for _, s := range list {
+		wg.Add(1)
+		go func(s *snapshot) {
+			defer wg.Done()
+			s.err = s.conn.Ping(ctx)
+		}(s)
+	}
+	wg.Wait()
+
+	addresses := map[string]*AddressStatus{}
+	var result []*AddressStatus
+	for _, s := range list {
+		status, ok := addresses[s.opts.address()]
+		if !ok {
+			status = &AddressStatus{
+				Address: s.opts.address(),
+				Healthy: true,
+			}
+			addresses[status.Address] = status
+			result = append(result, status)
+		}
+		status.Connections++
+		status.References += s.refs
+		if s.err != nil {
+			status.Healthy = false
+			status.Errors = append(status.Errors, fmt.Sprintf("client %s: %s", s.clientId, s.err.Error()))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address < result[j].Address
+	})
+	return result
+}
+
+func dial(ctx context.Context, kind string, host string, port int, opts Options, log *logger.Logger) (conn, error) {
+	if kind == kindQueues {
+		options := []queues_stream.Option{
+			queues_stream.WithAddress(host, port),
+			queues_stream.WithClientId(opts.ClientId),
+			queues_stream.WithCheckConnection(true),
+			queues_stream.WithAutoReconnect(true),
+			queues_stream.WithAuthToken(opts.AuthToken),
+			queues_stream.WithConnectionNotificationFunc(
+				func(msg string) {
+					log.Infof("connection: %s, %s", opts.address(), msg)
+				}),
+		}
+		if opts.TLS.Enabled {
+			config, err := tlsconfig.ClientConfig(opts.TLS, host, log)
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, queues_stream.WithTLSConfig(config))
+		}
+		client, err := queues_stream.NewQueuesStreamClient(ctx, options...)
+		if err != nil {
+			return nil, err
+		}
+		return queuesConn{client}, nil
+	}
+	options := []kubemq.Option{
+		kubemq.WithAddress(host, port),
+		kubemq.WithClientId(opts.ClientId),
+		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
+		kubemq.WithCheckConnection(true),
+		kubemq.WithAuthToken(opts.AuthToken),
+		kubemq.WithMaxReconnects(opts.MaxReconnects),
+		kubemq.WithAutoReconnect(opts.AutoReconnect),
+		kubemq.WithReconnectInterval(opts.ReconnectInterval),
+	}
+	if opts.TLS.Enabled {
+		config, err := tlsconfig.ClientConfig(opts.TLS, host, log)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, kubemq.WithTLSConfig(config))
+	}
+	client, err := kubemq.NewClient(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return clientConn{client}, nil
+}
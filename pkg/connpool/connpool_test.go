@@ -0,0 +1,221 @@
+package connpool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+type fakeConn struct {
+	opts    Options
+	closed  *atomic.Int32
+	pingErr error
+}
+
+func (f *fakeConn) value() interface{} {
+	return f
+}
+
+func (f *fakeConn) Ping(ctx context.Context) error {
+	return f.pingErr
+}
+
+func (f *fakeConn) Close() error {
+	f.closed.Inc()
+	return nil
+}
+
+type fakeDialer struct {
+	mu      sync.Mutex
+	dials   int
+	closed  *atomic.Int32
+	delay   time.Duration
+	failing map[string]bool
+	pingErr map[string]error
+}
+
+func newFakeDialer() *fakeDialer {
+	return &fakeDialer{
+		closed:  atomic.NewInt32(0),
+		failing: map[string]bool{},
+		pingErr: map[string]error{},
+	}
+}
+
+func (d *fakeDialer) dial(ctx context.Context, kind string, host string, port int, opts Options, log *logger.Logger) (conn, error) {
+	time.Sleep(d.delay)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.failing[opts.ClientId] {
+		return nil, fmt.Errorf("connection refused")
+	}
+	d.dials++
+	return &fakeConn{opts: opts, closed: d.closed, pingErr: d.pingErr[opts.ClientId]}, nil
+}
+
+func (d *fakeDialer) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dials
+}
+
+func TestManager_Share(t *testing.T) {
+	d := newFakeDialer()
+	m := newManager(d.dial)
+	ctx := context.Background()
+	opts := Options{Host: "localhost", Port: 50000, ClientId: "client"}
+
+	first, err := m.acquire(ctx, kindClient, opts)
+	require.NoError(t, err)
+	second, err := m.acquire(ctx, kindClient, opts)
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, 1, d.count())
+
+	other := []Options{
+		{Host: "localhost", Port: 50000, ClientId: "other"},
+		{Host: "localhost", Port: 50000, ClientId: "client", AuthToken: "token"},
+		{Host: "localhost", Port: 50000, ClientId: "client", Instance: 1},
+		{Host: "remote", Port: 50000, ClientId: "client"},
+	}
+	for _, o := range other {
+		e, err := m.acquire(ctx, kindClient, o)
+		require.NoError(t, err)
+		require.NotSame(t, first, e)
+	}
+	queues, err := m.acquire(ctx, kindQueues, opts)
+	require.NoError(t, err)
+	require.NotSame(t, first, queues)
+	require.Equal(t, 6, d.count())
+
+	require.NoError(t, m.Release(first.conn.value()))
+	require.Equal(t, int32(0), d.closed.Load())
+	require.NoError(t, m.Release(first.conn.value()))
+	require.Equal(t, int32(1), d.closed.Load())
+	require.Error(t, m.Release(first.conn.value()))
+
+	third, err := m.acquire(ctx, kindClient, opts)
+	require.NoError(t, err)
+	require.NotSame(t, first, third)
+	require.Equal(t, 7, d.count())
+}
+
+func TestManager_DefaultClientId(t *testing.T) {
+	d := newFakeDialer()
+	m := newManager(d.dial)
+	ctx := context.Background()
+	opts := Options{Host: "localhost", Port: 50000}
+
+	first, err := m.acquire(ctx, kindClient, opts)
+	require.NoError(t, err)
+	second, err := m.acquire(ctx, kindClient, opts)
+	require.NoError(t, err)
+	require.Same(t, first, second)
+	require.Equal(t, m.clientId, first.conn.(*fakeConn).opts.ClientId)
+
+	instance, err := m.acquire(ctx, kindClient, Options{Host: "localhost", Port: 50000, Instance: 1})
+	require.NoError(t, err)
+	require.NotSame(t, first, instance)
+	require.Equal(t, m.clientId+"-1", instance.conn.(*fakeConn).opts.ClientId)
+
+	exclusive := Options{Host: "localhost", Port: 50000, Exclusive: true}
+	third, err := m.acquire(ctx, kindClient, exclusive)
+	require.NoError(t, err)
+	fourth, err := m.acquire(ctx, kindClient, exclusive)
+	require.NoError(t, err)
+	require.NotSame(t, first, third)
+	require.NotSame(t, third, fourth)
+	require.NotEqual(t, m.clientId, third.conn.(*fakeConn).opts.ClientId)
+	require.NotEqual(t, third.conn.(*fakeConn).opts.ClientId, fourth.conn.(*fakeConn).opts.ClientId)
+
+	named, err := m.acquire(ctx, kindClient, Options{Host: "localhost", Port: 50000, ClientId: "client", Exclusive: true})
+	require.NoError(t, err)
+	require.Equal(t, "client", named.conn.(*fakeConn).opts.ClientId)
+	require.Equal(t, 5, d.count())
+
+	require.NoError(t, m.Release(third.conn.value()))
+	require.Equal(t, int32(1), d.closed.Load())
+}
+
+func TestManager_DialError(t *testing.T) {
+	d := newFakeDialer()
+	d.failing["client"] = true
+	m := newManager(d.dial)
+	opts := Options{Host: "localhost", Port: 50000, ClientId: "client"}
+	_, err := m.acquire(context.Background(), kindClient, opts)
+	require.Error(t, err)
+	require.Empty(t, m.entries)
+
+	d.mu.Lock()
+	d.failing["client"] = false
+	d.mu.Unlock()
+	_, err = m.acquire(context.Background(), kindClient, opts)
+	require.NoError(t, err)
+}
+
+func TestManager_ConcurrentAcquire(t *testing.T) {
+	d := newFakeDialer()
+	d.delay = 50 * time.Millisecond
+	m := newManager(d.dial)
+	opts := Options{Host: "localhost", Port: 50000, ClientId: "client"}
+	wg := sync.WaitGroup{}
+	entries := make([]*entry, 10)
+	for i := range entries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e, err := m.acquire(context.Background(), kindClient, opts)
+			require.NoError(t, err)
+			entries[i] = e
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, 1, d.count())
+	for _, e := range entries {
+		require.Same(t, entries[0], e)
+	}
+	require.Equal(t, 10, entries[0].refs)
+	for _, e := range entries {
+		require.NoError(t, m.Release(e.conn.value()))
+	}
+	require.Equal(t, int32(1), d.closed.Load())
+	require.Empty(t, m.entries)
+	require.Empty(t, m.values)
+}
+
+func TestManager_Status(t *testing.T) {
+	d := newFakeDialer()
+	d.pingErr["down"] = fmt.Errorf("unavailable")
+	m := newManager(d.dial)
+	ctx := context.Background()
+	for _, opts := range []Options{
+		{Host: "cluster-a", Port: 50000, ClientId: "source"},
+		{Host: "cluster-a", Port: 50000, ClientId: "source"},
+		{Host: "cluster-a", Port: 50000, ClientId: "target"},
+		{Host: "cluster-b", Port: 50000, ClientId: "down"},
+	} {
+		_, err := m.acquire(ctx, kindClient, opts)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []*AddressStatus{
+		{
+			Address:     "cluster-a:50000",
+			Connections: 2,
+			References:  3,
+			Healthy:     true,
+		},
+		{
+			Address:     "cluster-b:50000",
+			Connections: 1,
+			References:  1,
+			Healthy:     false,
+			Errors:      []string{"client down: unavailable"},
+		},
+	}, m.Status(ctx))
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"sync"
+	"time"
+)
+
+type run struct {
+	name    string
+	last    uint64
+	missing map[uint64]struct{}
+	seen    time.Time
+}
+
+// Checker detects sequence gaps in the messages received from generators. Each generator run is
+// tracked by its run id, a new run id starts a new sequence without reporting a gap.
+//
+// Messages may arrive out of order up to window sequences behind the last received one, so a
+// missing sequence is counted only when a sequence more than window after it was received. Runs
+// without messages for the idle duration are evicted and their pending missing sequences are counted.
+type Checker struct {
+	mu        sync.Mutex
+	window    uint64
+	idle      time.Duration
+	runs      map[string]*run
+	gaps      map[string]uint64
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewChecker(window uint64, idle time.Duration) *Checker {
+	return &Checker{
+		window:    window,
+		idle:      idle,
+		runs:      map[string]*run{},
+		gaps:      map[string]uint64{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Check records a received sequence and returns the number of messages counted missing by it. A
+// duplicated message, or a late message within the window, returns zero.
+func (c *Checker) Check(runId string, sequence uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.check("", runId, sequence)
+}
+
+// CheckNamed records a received sequence like Check and adds the missing messages to the gaps
+// count of the generator name.
+func (c *Checker) CheckNamed(name, runId string, sequence uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	missing := c.check(name, runId, sequence)
+	c.gaps[name] += missing
+	return missing
+}
+
+// Gaps returns the missing messages counted per generator name by CheckNamed.
+func (c *Checker) Gaps() map[string]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gaps := make(map[string]uint64, len(c.gaps))
+	for name, count := range c.gaps {
+		gaps[name] = count
+	}
+	return gaps
+}
+
+// Runs returns the number of tracked runs.
+func (c *Checker) Runs() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.runs)
+}
+
+func (c *Checker) check(name, runId string, sequence uint64) uint64 {
+	now := c.now()
+	c.evict(now)
+	r, ok := c.runs[runId]
+	if !ok {
+		// the first message of a run seen by the checker, the receiver may have started mid run
+		c.runs[runId] = &run{name: name, last: sequence, missing: map[uint64]struct{}{}, seen: now}
+		return 0
+	}
+	r.seen = now
+	if sequence <= r.last {
+		delete(r.missing, sequence)
+		return 0
+	}
+	var counted uint64
+	lower := r.last + 1
+	if sequence > c.window && sequence-c.window > lower {
+		counted += sequence - c.window - lower
+		lower = sequence - c.window
+	}
+	for missing := lower; missing < sequence; missing++ {
+		r.missing[missing] = struct{}{}
+	}
+	r.last = sequence
+	for missing := range r.missing {
+		if r.last-missing > c.window {
+			delete(r.missing, missing)
+			counted++
+		}
+	}
+	return counted
+}
+
+// evict removes the runs idle for the idle duration, at most once per idle duration.
+func (c *Checker) evict(now time.Time) {
+	if c.idle <= 0 || now.Sub(c.lastSweep) < c.idle {
+		return
+	}
+	c.lastSweep = now
+	for runId, r := range c.runs {
+		if now.Sub(r.seen) < c.idle {
+			continue
+		}
+		if r.name != "" {
+			c.gaps[r.name] += uint64(len(r.missing))
+		}
+		delete(c.runs, runId)
+	}
+}
@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronSearch bounds the search of the next time of a cron schedule, an expression matching a
+// day that does not exist, such as "0 0 31 2 *", never fires.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{min: 0, max: 59}
+	hourField   = cronField{min: 0, max: 23}
+	domField    = cronField{min: 1, max: 31}
+	monthField  = cronField{min: 1, max: 12, names: monthNames}
+	// day of week 7 is sunday as well
+	dowField = cronField{min: 0, max: 7, names: dayNames}
+)
+
+// cronSchedule is a standard 5 fields cron expression: minute, hour, day of month, month and day
+// of week. When both days are restricted, a time matching either of them matches, as in cron.
+type cronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	domAll bool
+	dowAll bool
+	loc    *time.Location
+}
+
+func ParseCron(expr string, loc *time.Location) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if val, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = val
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %s, expected 5 fields", expr)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	s := &cronSchedule{
+		domAll: fields[2] == "*",
+		dowAll: fields[4] == "*",
+		loc:    loc,
+	}
+	var err error
+	for i, target := range []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow} {
+		field := []cronField{minuteField, hourField, domField, monthField, dowField}[i]
+		*target, err = field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %s, %w", expr, err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	if s.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("invalid cron expression %s, no matching time", expr)
+	}
+	return s, nil
+}
+
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		rangeValue, stepValue, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepValue)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %s", item)
+			}
+		}
+		from, to := f.min, f.max
+		switch {
+		case rangeValue == "*":
+		case strings.Contains(rangeValue, "-"):
+			fromValue, toValue, _ := strings.Cut(rangeValue, "-")
+			var err error
+			if from, err = f.value(fromValue); err != nil {
+				return 0, err
+			}
+			if to, err = f.value(toValue); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if from, err = f.value(rangeValue); err != nil {
+				return 0, err
+			}
+			to = from
+			if hasStep {
+				to = f.max
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("invalid range %s", item)
+		}
+		for i := from; i <= to; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(value string) (int, error) {
+	if val, ok := f.names[strings.ToLower(value)]; ok {
+		return val, nil
+	}
+	val, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", value)
+	}
+	if val < f.min || val > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", val, f.min, f.max)
+	}
+	return val, nil
+}
+
+// Next returns the first matching minute after t, or a zero time when there is none.
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(s.loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, s.loc).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAll || s.dowAll {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
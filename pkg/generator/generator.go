@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"context"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"go.uber.org/atomic"
+)
+
+// Schedule returns the next time to generate a message after t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type interval time.Duration
+
+// Every returns a schedule generating a message every d.
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// Tags set by the timer source on every generated message.
+const (
+	TagName      = "timer.name"
+	TagRunId     = "timer.run_id"
+	TagSequence  = "timer.sequence"
+	TagTimestamp = "timer.timestamp"
+)
+
+// Tick is a scheduled message. Sequences start at 1 on every run, so a receiver detects a lost
+// message by a gap in the sequences of the same run id.
+type Tick struct {
+	Name     string
+	RunId    string
+	Sequence uint64
+	Time     time.Time
+}
+
+type Stats struct {
+	Name      string `json:"name"`
+	RunId     string `json:"run_id"`
+	Generated uint64 `json:"generated"`
+	Failed    uint64 `json:"failed"`
+	Sequence  uint64 `json:"sequence"`
+}
+
+// Generator calls an emit function on a schedule and counts the generated and failed messages.
+type Generator struct {
+	name      string
+	runId     string
+	schedule  Schedule
+	sequence  *atomic.Uint64
+	generated *atomic.Uint64
+	failed    *atomic.Uint64
+}
+
+func New(name string, schedule Schedule) *Generator {
+	return &Generator{
+		name:      name,
+		runId:     uuid.New().String(),
+		schedule:  schedule,
+		sequence:  atomic.NewUint64(0),
+		generated: atomic.NewUint64(0),
+		failed:    atomic.NewUint64(0),
+	}
+}
+
+// Run emits the ticks until ctx is done. Ticks are emitted one at a time; scheduled times passed
+// while a tick is emitted are skipped without using a sequence.
+func (g *Generator) Run(ctx context.Context, emit func(ctx context.Context, tick Tick) error) {
+	next := g.schedule.Next(time.Now())
+	for !next.IsZero() {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		tick := Tick{
+			Name:     g.name,
+			RunId:    g.runId,
+			Sequence: g.sequence.Inc(),
+			Time:     next,
+		}
+		if err := emit(ctx, tick); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			g.failed.Inc()
+		} else {
+			g.generated.Inc()
+		}
+		now := time.Now()
+		for next = g.schedule.Next(next); !next.IsZero() && !next.After(now); {
+			next = g.schedule.Next(next)
+		}
+	}
+}
+
+func (g *Generator) Stats() Stats {
+	return Stats{
+		Name:      g.name,
+		RunId:     g.runId,
+		Generated: g.generated.Load(),
+		Failed:    g.failed.Load(),
+		Sequence:  g.sequence.Load(),
+	}
+}
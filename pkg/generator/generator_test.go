@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 30, 20, 0, time.UTC) // monday
+	tests := []struct {
+		expr    string
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			expr: "* * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 10, 32, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "*/15 * * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "0 9-17/4 * * *",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "0 0 * * sat,sun",
+			want: []time.Time{
+				time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "0 0 * * 7",
+			want: []time.Time{
+				time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// day of month or day of week
+			expr: "0 12 1 * mon",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 22, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 29, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "0 0 29 feb *",
+			want: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "@hourly",
+			want: []time.Time{
+				time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{expr: "* * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* * * 13 *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "5-1 * * * *", wantErr: true},
+		{expr: "0 0 31 2 *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr, time.UTC)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			next := base
+			for _, want := range tt.want {
+				next = s.Next(next)
+				require.Equal(t, want, next)
+			}
+		})
+	}
+}
+
+func TestParseCron_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+5:30", 5*3600+1800)
+	s, err := ParseCron("0 9 * * *", loc)
+	require.NoError(t, err)
+	next := s.Next(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, 1, 15, 3, 30, 0, 0, time.UTC), next.UTC())
+}
+
+type emitted struct {
+	mu    sync.Mutex
+	ticks []Tick
+}
+
+func (e *emitted) list() []Tick {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Tick{}, e.ticks...)
+}
+
+func TestGenerator_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := New("heartbeat", Every(20*time.Millisecond))
+	e := &emitted{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.Run(ctx, func(ctx context.Context, tick Tick) error {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.ticks = append(e.ticks, tick)
+			if tick.Sequence%2 == 0 {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool {
+		return len(e.list()) >= 4
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	ticks := e.list()
+	for i, tick := range ticks {
+		require.Equal(t, "heartbeat", tick.Name)
+		require.Equal(t, g.Stats().RunId, tick.RunId)
+		require.Equal(t, uint64(i+1), tick.Sequence)
+		if i > 0 {
+			require.Equal(t, 20*time.Millisecond, tick.Time.Sub(ticks[i-1].Time))
+		}
+	}
+	stats := g.Stats()
+	require.Equal(t, uint64(len(ticks)), stats.Sequence)
+	require.Equal(t, stats.Sequence, stats.Generated+stats.Failed)
+	require.Equal(t, uint64(len(ticks)/2), stats.Failed)
+	require.NotEqual(t, stats.RunId, New("heartbeat", Every(time.Second)).Stats().RunId)
+}
+
+func TestGenerator_SkipsMissedTicks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g := New("heartbeat", Every(10*time.Millisecond))
+	e := &emitted{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.Run(ctx, func(ctx context.Context, tick Tick) error {
+			e.mu.Lock()
+			e.ticks = append(e.ticks, tick)
+			e.mu.Unlock()
+			if tick.Sequence == 1 {
+				time.Sleep(55 * time.Millisecond)
+			}
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool {
+		return len(e.list()) >= 2
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	ticks := e.list()
+	require.Equal(t, uint64(2), ticks[1].Sequence)
+	require.GreaterOrEqual(t, ticks[1].Time.Sub(ticks[0].Time), 50*time.Millisecond)
+}
+
+func TestChecker(t *testing.T) {
+	c := NewChecker(0, time.Minute)
+	require.Equal(t, uint64(0), c.Check("a", 5))
+	require.Equal(t, uint64(0), c.Check("a", 6))
+	require.Equal(t, uint64(2), c.Check("a", 9))
+	require.Equal(t, uint64(0), c.Check("a", 8))
+	require.Equal(t, uint64(0), c.Check("b", 1))
+	require.Equal(t, uint64(1), c.Check("a", 11))
+	require.Equal(t, uint64(0), c.Check("b", 2))
+}
+
+func TestChecker_CheckNamed(t *testing.T) {
+	c := NewChecker(0, time.Minute)
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 1))
+	require.Equal(t, uint64(2), c.CheckNamed("heartbeat", "run-1", 4))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-2", 1))
+	require.Equal(t, uint64(1), c.CheckNamed("heartbeat", "run-2", 3))
+	require.Equal(t, uint64(0), c.CheckNamed("probe", "run-3", 7))
+	require.Equal(t, map[string]uint64{"heartbeat": 3, "probe": 0}, c.Gaps())
+}
+
+func TestChecker_Window(t *testing.T) {
+	c := NewChecker(2, time.Minute)
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 1))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 3))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 4))
+	// 2 arrives late within the window
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 2))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 6))
+	// 5 is counted when 8 is received, more than the window after it
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 7))
+	require.Equal(t, uint64(1), c.CheckNamed("heartbeat", "run-1", 8))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 5))
+	// a large gap counts the sequences before the window at once
+	require.Equal(t, uint64(9), c.CheckNamed("heartbeat", "run-1", 20))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 18))
+	require.Equal(t, uint64(1), c.CheckNamed("heartbeat", "run-1", 22))
+	require.Equal(t, map[string]uint64{"heartbeat": 11}, c.Gaps())
+}
+
+func TestChecker_Evict(t *testing.T) {
+	now := time.Now()
+	c := NewChecker(5, time.Minute)
+	c.now = func() time.Time { return now }
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 1))
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 4))
+	require.Equal(t, uint64(0), c.CheckNamed("probe", "run-2", 1))
+	now = now.Add(30 * time.Second)
+	require.Equal(t, uint64(0), c.CheckNamed("probe", "run-2", 2))
+	now = now.Add(40 * time.Second)
+	require.Equal(t, uint64(0), c.CheckNamed("probe", "run-2", 3))
+	// run-1 was idle for more than a minute, its missing 2 and 3 are counted on eviction
+	require.Equal(t, 1, c.Runs())
+	require.Equal(t, map[string]uint64{"heartbeat": 2, "probe": 0}, c.Gaps())
+	require.Equal(t, uint64(0), c.CheckNamed("heartbeat", "run-1", 9))
+	require.Equal(t, 2, c.Runs())
+	require.Equal(t, map[string]uint64{"heartbeat": 2, "probe": 0}, c.Gaps())
+}
@@ -0,0 +1,217 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	SourceCommand     = "source.command"
+	SourceQuery       = "source.query"
+	SourceEvents      = "source.events"
+	SourceEventsStore = "source.events-store"
+	SourceQueue       = "source.queue"
+	SourceHTTP        = "source.http"
+	SourceFile        = "source.file"
+	SourceTimer       = "source.timer"
+)
+
+// Acker settles the message on the source. It is set by sources with acknowledgement, which
+// keep the ownership of settling the message after the targets returned.
+type Acker interface {
+	Ack() error
+	NAck() error
+	ReQueue(channel string) error
+}
+
+// Message is the envelope sources pass to the binding targets.
+type Message struct {
+	ID         string
+	Channel    string
+	Metadata   string
+	Body       []byte
+	Tags       map[string]string
+	Timestamp  time.Time
+	ReceivedAt time.Time
+	Source     string
+	Acker      Acker
+}
+
+// New returns the envelope of a request received by a source of the given kind.
+func New(source string, request interface{}) (*Message, error) {
+	m, err := From(request)
+	if err != nil {
+		return nil, err
+	}
+	m.Source = source
+	m.ReceivedAt = time.Now()
+	if m.Timestamp.IsZero() {
+		m.Timestamp = m.ReceivedAt
+	}
+	if val, ok := request.(Acker); ok {
+		m.Acker = val
+	}
+	return m, nil
+}
+
+// From returns a copy of the request fields as a message.
+func From(request interface{}) (*Message, error) {
+	switch val := request.(type) {
+	case *Message:
+		m := newMessage(val.ID, val.Channel, val.Metadata, val.Body, val.Tags)
+		m.Timestamp = val.Timestamp
+		m.ReceivedAt = val.ReceivedAt
+		m.Source = val.Source
+		m.Acker = val.Acker
+		return m, nil
+	case *kubemq.Event:
+		return newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags), nil
+	case *kubemq.EventStoreReceive:
+		m := newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags)
+		m.Timestamp = val.Timestamp
+		return m, nil
+	case *kubemq.CommandReceive:
+		return newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags), nil
+	case *kubemq.QueryReceive:
+		return newMessage(val.Id, val.Channel, val.Metadata, val.Body, val.Tags), nil
+	case *kubemq.QueueMessage:
+		m := newMessage(val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+		if val.Attributes != nil && val.Attributes.Timestamp > 0 {
+			m.Timestamp = time.Unix(0, val.Attributes.Timestamp)
+		}
+		return m, nil
+	case *queues_stream.QueueMessage:
+		m := newMessage(val.MessageID, val.Channel, val.Metadata, val.Body, val.Tags)
+		if val.Attributes != nil && val.Attributes.Timestamp > 0 {
+			m.Timestamp = time.Unix(0, val.Attributes.Timestamp)
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+}
+
+func newMessage(id, channel, metadata string, body []byte, tags map[string]string) *Message {
+	m := &Message{
+		ID:       id,
+		Channel:  channel,
+		Metadata: metadata,
+		Body:     body,
+		Tags:     map[string]string{},
+	}
+	for key, value := range tags {
+		m.Tags[key] = value
+	}
+	return m
+}
+
+// Apply returns a copy of request of the same type with the message fields set, leaving the
+// original request untouched for other targets and for the source acknowledgement.
+func (m *Message) Apply(request interface{}) (interface{}, error) {
+	switch val := request.(type) {
+	case *Message:
+		msg := *val
+		msg.ID = m.ID
+		msg.Channel = m.Channel
+		msg.Metadata = m.Metadata
+		msg.Body = m.Body
+		msg.Tags = m.Tags
+		return &msg, nil
+	case *kubemq.Event:
+		return &kubemq.Event{
+			Id:       m.ID,
+			Channel:  m.Channel,
+			Metadata: m.Metadata,
+			Body:     m.Body,
+			ClientId: val.ClientId,
+			Tags:     m.Tags,
+		}, nil
+	case *kubemq.EventStoreReceive:
+		return &kubemq.EventStoreReceive{
+			Id:        m.ID,
+			Sequence:  val.Sequence,
+			Timestamp: val.Timestamp,
+			Channel:   m.Channel,
+			Metadata:  m.Metadata,
+			Body:      m.Body,
+			ClientId:  val.ClientId,
+			Tags:      m.Tags,
+		}, nil
+	case *kubemq.CommandReceive:
+		return &kubemq.CommandReceive{
+			Id:         m.ID,
+			ClientId:   val.ClientId,
+			Channel:    m.Channel,
+			Metadata:   m.Metadata,
+			Body:       m.Body,
+			ResponseTo: val.ResponseTo,
+			Tags:       m.Tags,
+		}, nil
+	case *kubemq.QueryReceive:
+		return &kubemq.QueryReceive{
+			Id:         m.ID,
+			Channel:    m.Channel,
+			ClientId:   val.ClientId,
+			Metadata:   m.Metadata,
+			Body:       m.Body,
+			ResponseTo: val.ResponseTo,
+			Tags:       m.Tags,
+		}, nil
+	case *kubemq.QueueMessage:
+		msg := kubemq.NewQueueMessage().
+			SetId(m.ID).
+			SetChannel(m.Channel).
+			SetMetadata(m.Metadata).
+			SetBody(m.Body).
+			SetTags(m.Tags)
+		msg.ClientID = val.ClientID
+		msg.Attributes = val.Attributes
+		msg.Policy = val.Policy
+		return msg, nil
+	case *queues_stream.QueueMessage:
+		msg := queues_stream.NewQueueMessage().
+			SetId(m.ID).
+			SetChannel(m.Channel).
+			SetMetadata(m.Metadata).
+			SetBody(m.Body).
+			SetTags(m.Tags)
+		msg.ClientID = val.ClientID
+		msg.Attributes = val.Attributes
+		msg.Policy = val.Policy
+		return msg, nil
+	default:
+		return nil, fmt.Errorf("unknown request type")
+	}
+}
+
+// ValidateKey checks a message key selector: id, channel, metadata, body or tag:<name>.
+func ValidateKey(key string) error {
+	switch {
+	case key == "id", key == "channel", key == "metadata", key == "body":
+		return nil
+	case strings.HasPrefix(key, "tag:") && len(key) > len("tag:"):
+		return nil
+	default:
+		return fmt.Errorf("invalid message key %s", key)
+	}
+}
+
+// Key returns the value of the message field selected by key.
+func (m *Message) Key(key string) string {
+	switch key {
+	case "id":
+		return m.ID
+	case "channel":
+		return m.Channel
+	case "metadata":
+		return m.Metadata
+	case "body":
+		return string(m.Body)
+	default:
+		return m.Tags[strings.TrimPrefix(key, "tag:")]
+	}
+}
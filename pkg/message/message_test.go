@@ -0,0 +1,103 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	pb "github.com/kubemq-io/protobuf/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	stored := time.Unix(1600000000, 0)
+	tags := map[string]string{"key": "value"}
+	queueMessage := queues_stream.NewQueueMessage().SetId("id").SetChannel("channel").SetMetadata("metadata").SetBody([]byte("body")).SetTags(tags)
+	queueMessage.Attributes = &pb.QueueMessageAttributes{Timestamp: stored.UnixNano()}
+	tests := []struct {
+		name          string
+		source        string
+		request       interface{}
+		wantTimestamp time.Time
+		wantAcker     bool
+		wantErr       bool
+	}{
+		{
+			name:    "command",
+			source:  SourceCommand,
+			request: &kubemq.CommandReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		},
+		{
+			name:    "query",
+			source:  SourceQuery,
+			request: &kubemq.QueryReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		},
+		{
+			name:    "events",
+			source:  SourceEvents,
+			request: &kubemq.Event{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		},
+		{
+			name:          "events store",
+			source:        SourceEventsStore,
+			request:       &kubemq.EventStoreReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags, Timestamp: stored},
+			wantTimestamp: stored,
+		},
+		{
+			name:          "queue",
+			source:        SourceQueue,
+			request:       queueMessage,
+			wantTimestamp: stored,
+			wantAcker:     true,
+		},
+		{
+			name:    "unknown",
+			source:  SourceQueue,
+			request: "some-request",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := New(tt.source, tt.request)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "id", msg.ID)
+			require.Equal(t, "channel", msg.Channel)
+			require.Equal(t, "metadata", msg.Metadata)
+			require.Equal(t, []byte("body"), msg.Body)
+			require.Equal(t, tags, msg.Tags)
+			require.Equal(t, tt.source, msg.Source)
+			require.False(t, msg.ReceivedAt.IsZero())
+			if tt.wantTimestamp.IsZero() {
+				require.Equal(t, msg.ReceivedAt, msg.Timestamp)
+			} else {
+				require.True(t, tt.wantTimestamp.Equal(msg.Timestamp))
+			}
+			require.Equal(t, tt.wantAcker, msg.Acker != nil)
+		})
+	}
+}
+
+func TestMessage_Apply(t *testing.T) {
+	original, err := New(SourceQueue, queues_stream.NewQueueMessage().SetId("id").SetChannel("channel").SetTags(map[string]string{"key": "value"}))
+	require.NoError(t, err)
+	msg, err := From(original)
+	require.NoError(t, err)
+	msg.Channel = "other-channel"
+	msg.Tags["key"] = "other-value"
+	applied, err := msg.Apply(original)
+	require.NoError(t, err)
+	result := applied.(*Message)
+	require.Equal(t, "other-channel", result.Channel)
+	require.Equal(t, "other-value", result.Tags["key"])
+	require.Equal(t, SourceQueue, result.Source)
+	require.Equal(t, original.ReceivedAt, result.ReceivedAt)
+	require.Equal(t, original.Acker, result.Acker)
+	require.Equal(t, "channel", original.Channel)
+	require.Equal(t, "value", original.Tags["key"])
+}
@@ -0,0 +1,35 @@
+// Package messagetest provides the source messages shared by the target tests.
+package messagetest
+
+import (
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"github.com/stretchr/testify/require"
+)
+
+// Requests returns the request every source kind receives for the same content: id "id", channel
+// "channel", metadata "metadata", body "body" and the tag "key" set to "value".
+func Requests() map[string]interface{} {
+	tags := map[string]string{"key": "value"}
+	return map[string]interface{}{
+		message.SourceCommand:     &kubemq.CommandReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		message.SourceQuery:       &kubemq.QueryReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		message.SourceEvents:      &kubemq.Event{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		message.SourceEventsStore: &kubemq.EventStoreReceive{Id: "id", Channel: "channel", Metadata: "metadata", Body: []byte("body"), Tags: tags},
+		message.SourceQueue:       queues_stream.NewQueueMessage().SetId("id").SetChannel("channel").SetMetadata("metadata").SetBody([]byte("body")).SetTags(tags),
+	}
+}
+
+// Messages returns the message every source kind passes to the targets for the requests.
+func Messages(t *testing.T) map[string]*message.Message {
+	messages := map[string]*message.Message{}
+	for source, request := range Requests() {
+		msg, err := message.New(source, request)
+		require.NoError(t, err)
+		messages[source] = msg
+	}
+	return messages
+}
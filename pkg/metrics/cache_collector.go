@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var cacheLabels = []string{"binding", "target_kind", "cache"}
+
+type cacheEntry struct {
+	binding    string
+	targetKind string
+	caches     []*cache.Cache
+}
+
+type cacheCollector struct {
+	entries    sync.Map
+	hitsDesc   *prometheus.Desc
+	missesDesc *prometheus.Desc
+	sizeDesc   *prometheus.Desc
+}
+
+func newCacheCollector() *cacheCollector {
+	return &cacheCollector{
+		hitsDesc: prometheus.NewDesc("kubemq_targets_cache_hits_count",
+			"counts cached responses per binding target", cacheLabels, nil),
+		missesDesc: prometheus.NewDesc("kubemq_targets_cache_misses_count",
+			"counts responses not found in cache per binding target", cacheLabels, nil),
+		sizeDesc: prometheus.NewDesc("kubemq_targets_cache_size",
+			"number of local cache entries per binding target", cacheLabels, nil),
+	}
+}
+
+func (c *cacheCollector) add(binding, targetKind string, caches []*cache.Cache) {
+	c.entries.Store(binding, &cacheEntry{
+		binding:    binding,
+		targetKind: targetKind,
+		caches:     caches,
+	})
+}
+
+func (c *cacheCollector) remove(binding string) {
+	c.entries.Delete(binding)
+}
+
+func (c *cacheCollector) counts(binding, targetKind string) (float64, float64) {
+	val, ok := c.entries.Load(binding)
+	if !ok || val.(*cacheEntry).targetKind != targetKind {
+		return 0, 0
+	}
+	var hits, misses float64
+	for _, ch := range val.(*cacheEntry).caches {
+		stats := ch.Stats()
+		hits += float64(stats.Hits)
+		misses += float64(stats.Misses)
+	}
+	return hits, misses
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hitsDesc
+	ch <- c.missesDesc
+	ch <- c.sizeDesc
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.entries.Range(func(key, value interface{}) bool {
+		entry := value.(*cacheEntry)
+		for _, cc := range entry.caches {
+			stats := cc.Stats()
+			lbs := []string{entry.binding, entry.targetKind, stats.Name}
+			ch <- prometheus.MustNewConstMetric(c.hitsDesc, prometheus.CounterValue, float64(stats.Hits), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.missesDesc, prometheus.CounterValue, float64(stats.Misses), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.sizeDesc, prometheus.GaugeValue, float64(stats.Size), lbs...)
+		}
+		return true
+	})
+}
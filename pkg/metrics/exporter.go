@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"net/http"
@@ -15,6 +18,10 @@ type Exporter struct {
 	requestsVolumeCollector  *promCounterMetric
 	responsesVolumeCollector *promCounterMetric
 	errorsCollector          *promCounterMetric
+	droppedLoopsCollector    *promCounterMetric
+	poolCollector            *poolCollector
+	cacheCollector           *cacheCollector
+	generatorCollector       *generatorCollector
 }
 
 func (e *Exporter) PrometheusHandler() http.Handler {
@@ -29,6 +36,10 @@ func NewExporter() (*Exporter, error) {
 		requestsVolumeCollector:  nil,
 		responsesVolumeCollector: nil,
 		errorsCollector:          nil,
+		droppedLoopsCollector:    nil,
+		poolCollector:            newPoolCollector(),
+		cacheCollector:           newCacheCollector(),
+		generatorCollector:       newGeneratorCollector(),
 	}
 	if err := e.initPromMetrics(); err != nil {
 		return nil, err
@@ -67,6 +78,12 @@ func (e *Exporter) initPromMetrics() error {
 		"counts error requests per binding,source and target types",
 		labels...,
 	)
+	e.droppedLoopsCollector = newPromCounterMetric(
+		"loops",
+		"dropped_count",
+		"counts messages dropped by loop prevention per binding,source and target types",
+		labels...,
+	)
 
 	err := prometheus.Register(e.requestsCollector.metric)
 	if err != nil {
@@ -88,6 +105,22 @@ func (e *Exporter) initPromMetrics() error {
 	if err != nil {
 		return err
 	}
+	err = prometheus.Register(e.droppedLoopsCollector.metric)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.poolCollector)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.cacheCollector)
+	if err != nil {
+		return err
+	}
+	err = prometheus.Register(e.generatorCollector)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -99,5 +132,53 @@ func (e *Exporter) Report(m *Report) {
 	e.responsesCollector.add(m.ResponseCount, lbs)
 	e.responsesVolumeCollector.add(m.ResponseVolume, lbs)
 	e.errorsCollector.add(m.ErrorsCount, lbs)
+	e.droppedLoopsCollector.add(m.DroppedLoopsCount, lbs)
 	e.Store.Add(m)
 }
+
+func (e *Exporter) AddPools(binding, sourceKind string, pools []*pool.Pool) {
+	e.poolCollector.add(binding, sourceKind, pools)
+}
+
+func (e *Exporter) RemovePools(binding string) {
+	e.poolCollector.remove(binding)
+}
+
+func (e *Exporter) PoolStats() []pool.Stats {
+	return e.poolCollector.list()
+}
+
+func (e *Exporter) AddCaches(binding, targetKind string, caches []*cache.Cache) {
+	e.cacheCollector.add(binding, targetKind, caches)
+}
+
+func (e *Exporter) RemoveCaches(binding string) {
+	e.cacheCollector.remove(binding)
+}
+
+func (e *Exporter) AddGenerators(binding, sourceKind string, generators []*generator.Generator) {
+	e.generatorCollector.add(binding, sourceKind, generators)
+}
+
+func (e *Exporter) RemoveGenerators(binding string) {
+	e.generatorCollector.remove(binding)
+}
+
+func (e *Exporter) AddChecker(binding, sourceKind string, checker *generator.Checker) {
+	e.generatorCollector.addChecker(binding, sourceKind, checker)
+}
+
+func (e *Exporter) RemoveChecker(binding string) {
+	e.generatorCollector.removeChecker(binding)
+}
+
+// Reports returns the binding reports with the cache hits and misses of their targets.
+func (e *Exporter) Reports() []*Report {
+	var list []*Report
+	for _, report := range e.Store.List() {
+		r := report.Clone()
+		r.CacheHits, r.CacheMisses = e.cacheCollector.counts(r.Binding, r.TargetKind)
+		list = append(list, r)
+	}
+	return list
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var generatorLabels = []string{"binding", "source_kind", "generator"}
+
+type generatorEntry struct {
+	binding    string
+	sourceKind string
+	generators []*generator.Generator
+}
+
+type checkerEntry struct {
+	binding    string
+	sourceKind string
+	checker    *generator.Checker
+}
+
+type generatorCollector struct {
+	entries       sync.Map
+	checkers      sync.Map
+	generatedDesc *prometheus.Desc
+	failedDesc    *prometheus.Desc
+	sequenceDesc  *prometheus.Desc
+	gapsDesc      *prometheus.Desc
+}
+
+func newGeneratorCollector() *generatorCollector {
+	return &generatorCollector{
+		generatedDesc: prometheus.NewDesc("kubemq_targets_generator_generated_count",
+			"counts generated messages sent to the targets per binding source generator", generatorLabels, nil),
+		failedDesc: prometheus.NewDesc("kubemq_targets_generator_failed_count",
+			"counts generated messages failed on the targets per binding source generator", generatorLabels, nil),
+		sequenceDesc: prometheus.NewDesc("kubemq_targets_generator_sequence",
+			"last generated message sequence per binding source generator", generatorLabels, nil),
+		gapsDesc: prometheus.NewDesc("kubemq_targets_generator_gaps_count",
+			"counts generated messages missing on receive per binding source generator", generatorLabels, nil),
+	}
+}
+
+func (c *generatorCollector) add(binding, sourceKind string, generators []*generator.Generator) {
+	c.entries.Store(binding, &generatorEntry{
+		binding:    binding,
+		sourceKind: sourceKind,
+		generators: generators,
+	})
+}
+
+func (c *generatorCollector) remove(binding string) {
+	c.entries.Delete(binding)
+}
+
+func (c *generatorCollector) addChecker(binding, sourceKind string, checker *generator.Checker) {
+	c.checkers.Store(binding, &checkerEntry{
+		binding:    binding,
+		sourceKind: sourceKind,
+		checker:    checker,
+	})
+}
+
+func (c *generatorCollector) removeChecker(binding string) {
+	c.checkers.Delete(binding)
+}
+
+func (c *generatorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.generatedDesc
+	ch <- c.failedDesc
+	ch <- c.sequenceDesc
+	ch <- c.gapsDesc
+}
+
+func (c *generatorCollector) Collect(ch chan<- prometheus.Metric) {
+	c.entries.Range(func(key, value interface{}) bool {
+		entry := value.(*generatorEntry)
+		for _, g := range entry.generators {
+			stats := g.Stats()
+			lbs := []string{entry.binding, entry.sourceKind, stats.Name}
+			ch <- prometheus.MustNewConstMetric(c.generatedDesc, prometheus.CounterValue, float64(stats.Generated), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.failedDesc, prometheus.CounterValue, float64(stats.Failed), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.sequenceDesc, prometheus.GaugeValue, float64(stats.Sequence), lbs...)
+		}
+		return true
+	})
+	c.checkers.Range(func(key, value interface{}) bool {
+		entry := value.(*checkerEntry)
+		for name, gaps := range entry.checker.Gaps() {
+			lbs := []string{entry.binding, entry.sourceKind, name}
+			ch <- prometheus.MustNewConstMetric(c.gapsDesc, prometheus.CounterValue, float64(gaps), lbs...)
+		}
+		return true
+	})
+}
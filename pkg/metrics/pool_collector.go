@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var poolLabels = []string{"binding", "source_kind", "pool"}
+
+type poolEntry struct {
+	binding    string
+	sourceKind string
+	pools      []*pool.Pool
+}
+
+type poolCollector struct {
+	entries       sync.Map
+	workersDesc   *prometheus.Desc
+	busyDesc      *prometheus.Desc
+	queuedDesc    *prometheus.Desc
+	queueSizeDesc *prometheus.Desc
+	waitsDesc     *prometheus.Desc
+	rejectedDesc  *prometheus.Desc
+}
+
+func newPoolCollector() *poolCollector {
+	return &poolCollector{
+		workersDesc: prometheus.NewDesc("kubemq_targets_pool_workers",
+			"number of workers per binding source pool", poolLabels, nil),
+		busyDesc: prometheus.NewDesc("kubemq_targets_pool_busy",
+			"number of busy workers per binding source pool", poolLabels, nil),
+		queuedDesc: prometheus.NewDesc("kubemq_targets_pool_queued",
+			"number of queued tasks per binding source pool", poolLabels, nil),
+		queueSizeDesc: prometheus.NewDesc("kubemq_targets_pool_queue_size",
+			"queue depth per binding source pool", poolLabels, nil),
+		waitsDesc: prometheus.NewDesc("kubemq_targets_pool_waits_count",
+			"counts submissions blocked on a full pool per binding source pool", poolLabels, nil),
+		rejectedDesc: prometheus.NewDesc("kubemq_targets_pool_rejected_count",
+			"counts submissions dropped on a full pool per binding source pool", poolLabels, nil),
+	}
+}
+
+func (c *poolCollector) add(binding, sourceKind string, pools []*pool.Pool) {
+	c.entries.Store(binding, &poolEntry{
+		binding:    binding,
+		sourceKind: sourceKind,
+		pools:      pools,
+	})
+}
+
+func (c *poolCollector) remove(binding string) {
+	c.entries.Delete(binding)
+}
+
+func (c *poolCollector) list() []pool.Stats {
+	var list []pool.Stats
+	c.entries.Range(func(key, value interface{}) bool {
+		for _, p := range value.(*poolEntry).pools {
+			list = append(list, p.Stats())
+		}
+		return true
+	})
+	return list
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.workersDesc
+	ch <- c.busyDesc
+	ch <- c.queuedDesc
+	ch <- c.queueSizeDesc
+	ch <- c.waitsDesc
+	ch <- c.rejectedDesc
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	c.entries.Range(func(key, value interface{}) bool {
+		entry := value.(*poolEntry)
+		for _, p := range entry.pools {
+			stats := p.Stats()
+			lbs := []string{entry.binding, entry.sourceKind, stats.Name}
+			ch <- prometheus.MustNewConstMetric(c.workersDesc, prometheus.GaugeValue, float64(stats.Workers), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.busyDesc, prometheus.GaugeValue, float64(stats.Busy), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.queuedDesc, prometheus.GaugeValue, float64(stats.Queued), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.queueSizeDesc, prometheus.GaugeValue, float64(stats.QueueSize), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.waitsDesc, prometheus.CounterValue, float64(stats.Waits), lbs...)
+			ch <- prometheus.MustNewConstMetric(c.rejectedDesc, prometheus.CounterValue, float64(stats.Rejected), lbs...)
+		}
+		return true
+	})
+}
@@ -5,15 +5,18 @@ import (
 )
 
 type Report struct {
-	Key            string  `json:"-"`
-	Binding        string  `json:"binding"`
-	SourceKind     string  `json:"source_kind"`
-	TargetKind     string  `json:"target_kind"`
-	RequestCount   float64 `json:"request_count"`
-	RequestVolume  float64 `json:"request_volume"`
-	ResponseCount  float64 `json:"response_count"`
-	ResponseVolume float64 `json:"response_volume"`
-	ErrorsCount    float64 `json:"errors_count"`
+	Key               string  `json:"-"`
+	Binding           string  `json:"binding"`
+	SourceKind        string  `json:"source_kind"`
+	TargetKind        string  `json:"target_kind"`
+	RequestCount      float64 `json:"request_count"`
+	RequestVolume     float64 `json:"request_volume"`
+	ResponseCount     float64 `json:"response_count"`
+	ResponseVolume    float64 `json:"response_volume"`
+	ErrorsCount       float64 `json:"errors_count"`
+	DroppedLoopsCount float64 `json:"dropped_loops_count"`
+	CacheHits         float64 `json:"cache_hits"`
+	CacheMisses       float64 `json:"cache_misses"`
 }
 
 func (m *Report) labels() prometheus.Labels {
@@ -26,14 +29,17 @@ func (m *Report) labels() prometheus.Labels {
 
 func (m *Report) Clone() *Report {
 	return &Report{
-		Key:            m.Key,
-		Binding:        m.Binding,
-		SourceKind:     m.SourceKind,
-		TargetKind:     m.TargetKind,
-		RequestCount:   m.RequestCount,
-		RequestVolume:  m.RequestVolume,
-		ResponseCount:  m.ResponseCount,
-		ResponseVolume: m.ResponseVolume,
-		ErrorsCount:    m.ErrorsCount,
+		Key:               m.Key,
+		Binding:           m.Binding,
+		SourceKind:        m.SourceKind,
+		TargetKind:        m.TargetKind,
+		RequestCount:      m.RequestCount,
+		RequestVolume:     m.RequestVolume,
+		ResponseCount:     m.ResponseCount,
+		ResponseVolume:    m.ResponseVolume,
+		ErrorsCount:       m.ErrorsCount,
+		DroppedLoopsCount: m.DroppedLoopsCount,
+		CacheHits:         m.CacheHits,
+		CacheMisses:       m.CacheMisses,
 	}
 }
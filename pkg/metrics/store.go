@@ -20,6 +20,7 @@ func (s *Store) Add(report *Report) {
 		loaded.ResponseCount += report.ResponseCount
 		loaded.RequestVolume += report.RequestVolume
 		loaded.RequestCount += report.RequestCount
+		loaded.DroppedLoopsCount += report.DroppedLoopsCount
 	} else {
 		s.store.Store(report.Key, report.Clone())
 	}
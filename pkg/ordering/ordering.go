@@ -0,0 +1,72 @@
+package ordering
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+const (
+	ModeNone   = "none"
+	ModeGlobal = "global"
+	ModeKey    = "key"
+
+	defaultPartitions = 16
+)
+
+var modeMap = map[string]string{
+	"":         ModeNone,
+	ModeNone:   ModeNone,
+	ModeGlobal: ModeGlobal,
+	ModeKey:    ModeKey,
+}
+
+type Options struct {
+	Mode       string
+	Key        string
+	Partitions int
+}
+
+func ParseOptions(properties config.Metadata) (Options, error) {
+	o := Options{}
+	var err error
+	o.Mode, err = properties.ParseStringMap("ordering", modeMap)
+	if err != nil {
+		return Options{}, fmt.Errorf("error parsing ordering value, %w", err)
+	}
+	switch o.Mode {
+	case ModeGlobal:
+		o.Partitions = 1
+	case ModeKey:
+		o.Key, err = properties.MustParseString("ordering_key")
+		if err != nil {
+			return Options{}, fmt.Errorf("error parsing ordering key value, %w", err)
+		}
+		if err := message.ValidateKey(o.Key); err != nil {
+			return Options{}, fmt.Errorf("error parsing ordering key value, %w", err)
+		}
+		o.Partitions, err = properties.ParseIntWithRange("ordering_partitions", defaultPartitions, 1, 1024)
+		if err != nil {
+			return Options{}, fmt.Errorf("error parsing ordering partitions value, %w", err)
+		}
+	}
+	return o, nil
+}
+
+func (o Options) Enabled() bool {
+	return o.Mode == ModeGlobal || o.Mode == ModeKey
+}
+
+// PartitionKey returns the ordering key of the request. All requests share the empty key in
+// global mode.
+func (o Options) PartitionKey(request interface{}) string {
+	if o.Mode != ModeKey {
+		return ""
+	}
+	msg, err := message.From(request)
+	if err != nil {
+		return ""
+	}
+	return msg.Key(o.Key)
+}
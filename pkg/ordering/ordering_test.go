@@ -0,0 +1,79 @@
+package ordering
+
+import (
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties config.Metadata
+		want       Options
+		wantErr    bool
+	}{
+		{
+			name:       "default",
+			properties: nil,
+			want:       Options{Mode: ModeNone},
+		},
+		{
+			name:       "global",
+			properties: config.Metadata{"ordering": "global"},
+			want:       Options{Mode: ModeGlobal, Partitions: 1},
+		},
+		{
+			name:       "key",
+			properties: config.Metadata{"ordering": "key", "ordering_key": "tag:account", "ordering_partitions": "4"},
+			want:       Options{Mode: ModeKey, Key: "tag:account", Partitions: 4},
+		},
+		{
+			name:       "key - default partitions",
+			properties: config.Metadata{"ordering": "key", "ordering_key": "metadata"},
+			want:       Options{Mode: ModeKey, Key: "metadata", Partitions: defaultPartitions},
+		},
+		{
+			name:       "invalid - mode",
+			properties: config.Metadata{"ordering": "bad"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid - no key",
+			properties: config.Metadata{"ordering": "key"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid - bad key",
+			properties: config.Metadata{"ordering": "key", "ordering_key": "tag:"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid - bad partitions",
+			properties: config.Metadata{"ordering": "key", "ordering_key": "id", "ordering_partitions": "0"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.properties)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOptions_PartitionKey(t *testing.T) {
+	event := &kubemq.EventStoreReceive{Id: "id", Metadata: "meta", Tags: map[string]string{"account": "a1"}}
+	require.Equal(t, "a1", Options{Mode: ModeKey, Key: "tag:account"}.PartitionKey(event))
+	require.Equal(t, "meta", Options{Mode: ModeKey, Key: "metadata"}.PartitionKey(event))
+	require.Equal(t, "", Options{Mode: ModeGlobal}.PartitionKey(event))
+	require.False(t, Options{Mode: ModeNone}.Enabled())
+	require.True(t, Options{Mode: ModeGlobal}.Enabled())
+}
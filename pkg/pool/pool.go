@@ -0,0 +1,165 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+type Stats struct {
+	Name      string `json:"name"`
+	Workers   int    `json:"workers"`
+	Busy      int    `json:"busy"`
+	Queued    int    `json:"queued"`
+	QueueSize int    `json:"queue_size"`
+	Waits     int64  `json:"waits"`
+	Rejected  int64  `json:"rejected"`
+}
+
+type Pool struct {
+	name     string
+	workers  int
+	queues   []chan func()
+	busy     *atomic.Int32
+	waits    *atomic.Int64
+	rejected *atomic.Int64
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func New(name string, workers, queueSize int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return newPool(name, workers, queueSize, 1)
+}
+
+// NewPartitioned returns a pool where every worker owns its queue. Tasks submitted with the
+// same key always land on the same worker and run one after another in submission order.
+func NewPartitioned(name string, partitions, queueSize int) *Pool {
+	if partitions < 1 {
+		partitions = 1
+	}
+	return newPool(name, partitions, queueSize, partitions)
+}
+
+func newPool(name string, workers, queueSize, queues int) *Pool {
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	p := &Pool{
+		name:     name,
+		workers:  workers,
+		busy:     atomic.NewInt32(0),
+		waits:    atomic.NewInt64(0),
+		rejected: atomic.NewInt64(0),
+		done:     make(chan struct{}),
+	}
+	for i := 0; i < queues; i++ {
+		p.queues = append(p.queues, make(chan func(), queueSize))
+	}
+	return p
+}
+
+func (p *Pool) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go p.run(ctx, p.queues[i%len(p.queues)])
+	}
+}
+
+func (p *Pool) run(ctx context.Context, queue chan func()) {
+	for {
+		select {
+		case task := <-queue:
+			p.busy.Inc()
+			task()
+			p.busy.Dec()
+		case <-p.done:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Submit queues the task for execution, blocking while the pool is saturated so the
+// caller stops pulling new messages until a worker frees up.
+func (p *Pool) Submit(ctx context.Context, task func()) error {
+	return p.submit(ctx, p.queues[0], task)
+}
+
+// SubmitKey queues the task on the partition owning key. On a shared pool it behaves like Submit.
+func (p *Pool) SubmitKey(ctx context.Context, key string, task func()) error {
+	return p.submit(ctx, p.partition(key), task)
+}
+
+func (p *Pool) partition(key string) chan func() {
+	if len(p.queues) == 1 {
+		return p.queues[0]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return p.queues[h.Sum32()%uint32(len(p.queues))]
+}
+
+func (p *Pool) submit(ctx context.Context, queue chan func(), task func()) error {
+	select {
+	case queue <- task:
+		return nil
+	default:
+	}
+	p.waits.Inc()
+	select {
+	case queue <- task:
+		return nil
+	case <-p.done:
+		return fmt.Errorf("pool %s is stopped", p.name)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit queues the task only if the pool has room and reports whether it was queued.
+func (p *Pool) TrySubmit(task func()) bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+	}
+	select {
+	case p.queues[0] <- task:
+		return true
+	default:
+		p.rejected.Inc()
+		return false
+	}
+}
+
+func (p *Pool) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// Done is closed when the pool is stopped. Tasks still queued at that time are never run.
+func (p *Pool) Done() <-chan struct{} {
+	return p.done
+}
+
+func (p *Pool) Stats() Stats {
+	stats := Stats{
+		Name:     p.name,
+		Workers:  p.workers,
+		Busy:     int(p.busy.Load()),
+		Waits:    p.waits.Load(),
+		Rejected: p.rejected.Load(),
+	}
+	for _, queue := range p.queues {
+		stats.Queued += len(queue)
+		stats.QueueSize += cap(queue)
+	}
+	return stats
+}
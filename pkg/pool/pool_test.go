@@ -0,0 +1,149 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestPool_Submit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := New("test", 4, 10)
+	p.Start(ctx)
+	defer p.Stop()
+	wg := sync.WaitGroup{}
+	executed := atomic.NewInt32(0)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		err := p.Submit(ctx, func() {
+			executed.Inc()
+			wg.Done()
+		})
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	require.EqualValues(t, 100, executed.Load())
+}
+
+func TestPool_Backpressure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := New("test", 1, 1)
+	p.Start(ctx)
+	defer p.Stop()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, p.Submit(ctx, func() {
+		close(started)
+		<-release
+	}))
+	<-started
+	require.NoError(t, p.Submit(ctx, func() {}))
+	stats := p.Stats()
+	require.Equal(t, 1, stats.Busy)
+	require.Equal(t, 1, stats.Queued)
+
+	submitCtx, submitCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer submitCancel()
+	err := p.Submit(submitCtx, func() {})
+	require.Error(t, err)
+	require.EqualValues(t, 1, p.Stats().Waits)
+	close(release)
+}
+
+func TestPool_Stop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := New("test", 1, 0)
+	p.Start(ctx)
+	p.Stop()
+	p.Stop()
+	select {
+	case <-p.Done():
+	default:
+		require.Fail(t, "done is not closed after stop")
+	}
+	time.Sleep(100 * time.Millisecond)
+	err := p.Submit(ctx, func() {})
+	require.Error(t, err)
+}
+
+func TestPool_TrySubmit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := New("test", 1, 1)
+	p.Start(ctx)
+	defer p.Stop()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.True(t, p.TrySubmit(func() {
+		close(started)
+		<-release
+	}))
+	<-started
+	require.True(t, p.TrySubmit(func() {}))
+	require.False(t, p.TrySubmit(func() {}))
+	require.EqualValues(t, 1, p.Stats().Rejected)
+	close(release)
+}
+
+func TestPool_SubmitKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := NewPartitioned("test", 4, 10)
+	p.Start(ctx)
+	defer p.Stop()
+	mu := sync.Mutex{}
+	results := map[string][]int{}
+	wg := sync.WaitGroup{}
+	keys := []string{"a", "b", "c", "d", "e"}
+	for i := 0; i < 50; i++ {
+		for _, key := range keys {
+			i, key := i, key
+			wg.Add(1)
+			require.NoError(t, p.SubmitKey(ctx, key, func() {
+				defer wg.Done()
+				mu.Lock()
+				results[key] = append(results[key], i)
+				mu.Unlock()
+			}))
+		}
+	}
+	wg.Wait()
+	for _, key := range keys {
+		require.Len(t, results[key], 50)
+		for i, val := range results[key] {
+			require.Equal(t, i, val)
+		}
+	}
+	require.Equal(t, 4, p.Stats().Workers)
+	require.Equal(t, 40, p.Stats().QueueSize)
+}
+
+func TestPool_SubmitKeyBlocksPartition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := NewPartitioned("test", 2, 10)
+	p.Start(ctx)
+	defer p.Stop()
+	release := make(chan struct{})
+	require.NoError(t, p.SubmitKey(ctx, "a", func() {
+		<-release
+	}))
+	executed := atomic.NewBool(false)
+	done := make(chan struct{})
+	require.NoError(t, p.SubmitKey(ctx, "a", func() {
+		executed.Store(true)
+		close(done)
+	}))
+	time.Sleep(100 * time.Millisecond)
+	require.False(t, executed.Load())
+	close(release)
+	<-done
+	require.True(t, executed.Load())
+}
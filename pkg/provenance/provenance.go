@@ -0,0 +1,152 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+const (
+	DefaultPrefix = "x-kubemq-bridge-"
+
+	FieldOrigin        = "origin"
+	FieldSourceChannel = "source-channel"
+	FieldBinding       = "binding"
+	FieldInstance      = "instance"
+	FieldForwardedAt   = "forwarded-at"
+	FieldHops          = "hops"
+	FieldPath          = "path"
+)
+
+var AllFields = []string{FieldOrigin, FieldSourceChannel, FieldBinding, FieldInstance, FieldForwardedAt, FieldHops, FieldPath}
+
+var instanceID = loadInstanceID()
+
+func loadInstanceID() string {
+	if id := os.Getenv("KUBEMQ_BRIDGES_INSTANCE_ID"); id != "" {
+		return id
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return uuid.New().String()
+}
+
+func InstanceID() string {
+	return instanceID
+}
+
+type sourceKey struct{}
+
+// WithSource returns a context carrying the address of the cluster the source reads from.
+func WithSource(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, address)
+}
+
+func Source(ctx context.Context) string {
+	if val, ok := ctx.Value(sourceKey{}).(string); ok {
+		return val
+	}
+	return ""
+}
+
+type Hop struct {
+	Instance string `json:"instance"`
+	Binding  string `json:"binding"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Channel  string `json:"channel"`
+	Time     int64  `json:"time"`
+}
+
+type Options struct {
+	Prefix string
+	Fields map[string]bool
+}
+
+func (o Options) tag(field string) string {
+	return o.Prefix + field
+}
+
+func ParseFields(list []string) (map[string]bool, error) {
+	fields := map[string]bool{}
+	if len(list) == 0 {
+		list = AllFields
+	}
+	for _, field := range list {
+		field = strings.TrimSpace(field)
+		valid := false
+		for _, f := range AllFields {
+			if f == field {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid provenance field %s", field)
+		}
+		fields[field] = true
+	}
+	return fields, nil
+}
+
+func Hops(msg *message.Message, prefix string) int {
+	hops, err := strconv.Atoi(msg.Tags[prefix+FieldHops])
+	if err != nil {
+		return 0
+	}
+	return hops
+}
+
+func Path(msg *message.Message, prefix string) []Hop {
+	var path []Hop
+	val := msg.Tags[prefix+FieldPath]
+	if val == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(val), &path); err != nil {
+		return nil
+	}
+	return path
+}
+
+// Stamp records the hop on the message tags. Origin and source channel keep the values of the
+// first hop, hops is incremented and the hop is appended to the path.
+func Stamp(msg *message.Message, hop Hop, opts Options) {
+	if hop.Time == 0 {
+		hop.Time = time.Now().UnixMilli()
+	}
+	if opts.Fields[FieldOrigin] && msg.Tags[opts.tag(FieldOrigin)] == "" && hop.Source != "" {
+		msg.Tags[opts.tag(FieldOrigin)] = hop.Source
+	}
+	if opts.Fields[FieldSourceChannel] && msg.Tags[opts.tag(FieldSourceChannel)] == "" {
+		msg.Tags[opts.tag(FieldSourceChannel)] = hop.Channel
+	}
+	if opts.Fields[FieldBinding] {
+		msg.Tags[opts.tag(FieldBinding)] = hop.Binding
+	}
+	if opts.Fields[FieldInstance] {
+		msg.Tags[opts.tag(FieldInstance)] = hop.Instance
+	}
+	if opts.Fields[FieldForwardedAt] {
+		msg.Tags[opts.tag(FieldForwardedAt)] = time.UnixMilli(hop.Time).UTC().Format(time.RFC3339Nano)
+	}
+	if opts.Fields[FieldHops] {
+		msg.Tags[opts.tag(FieldHops)] = strconv.Itoa(Hops(msg, opts.Prefix) + 1)
+	}
+	if opts.Fields[FieldPath] {
+		path := append(Path(msg, opts.Prefix), hop)
+		if data, err := json.Marshal(path); err == nil {
+			msg.Tags[opts.tag(FieldPath)] = string(data)
+		}
+	}
+}
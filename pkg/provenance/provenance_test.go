@@ -0,0 +1,67 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStamp(t *testing.T) {
+	fields, err := ParseFields(nil)
+	require.NoError(t, err)
+	opts := Options{
+		Prefix: DefaultPrefix,
+		Fields: fields,
+	}
+	msg := &message.Message{
+		Channel: "events.a",
+		Tags:    map[string]string{"key": "value"},
+	}
+	Stamp(msg, Hop{
+		Instance: "bridge-1",
+		Binding:  "a-to-b",
+		Source:   "cluster-a:50000",
+		Target:   "cluster-b:50000",
+		Channel:  "events.a",
+		Time:     1000,
+	}, opts)
+	msg.Channel = "events.b"
+	Stamp(msg, Hop{
+		Instance: "bridge-2",
+		Binding:  "b-to-c",
+		Source:   "cluster-b:50000",
+		Target:   "cluster-c:50000",
+		Channel:  "events.b",
+		Time:     2000,
+	}, opts)
+	require.Equal(t, "value", msg.Tags["key"])
+	require.Equal(t, "cluster-a:50000", msg.Tags[DefaultPrefix+FieldOrigin])
+	require.Equal(t, "events.a", msg.Tags[DefaultPrefix+FieldSourceChannel])
+	require.Equal(t, "b-to-c", msg.Tags[DefaultPrefix+FieldBinding])
+	require.Equal(t, "bridge-2", msg.Tags[DefaultPrefix+FieldInstance])
+	require.Equal(t, "1970-01-01T00:00:02Z", msg.Tags[DefaultPrefix+FieldForwardedAt])
+	require.Equal(t, 2, Hops(msg, DefaultPrefix))
+	path := Path(msg, DefaultPrefix)
+	require.Len(t, path, 2)
+	require.Equal(t, "bridge-1", path[0].Instance)
+	require.Equal(t, "cluster-c:50000", path[1].Target)
+}
+
+func TestStamp_Fields(t *testing.T) {
+	fields, err := ParseFields([]string{"hops", "binding"})
+	require.NoError(t, err)
+	msg := &message.Message{Tags: map[string]string{}}
+	Stamp(msg, Hop{Binding: "binding"}, Options{Prefix: "p-", Fields: fields})
+	require.Equal(t, map[string]string{"p-hops": "1", "p-binding": "binding"}, msg.Tags)
+
+	_, err = ParseFields([]string{"bad-field"})
+	require.Error(t, err)
+}
+
+func TestSource(t *testing.T) {
+	require.Equal(t, "", Source(context.Background()))
+	ctx := WithSource(context.Background(), "localhost:50000")
+	require.Equal(t, "localhost:50000", Source(ctx))
+}
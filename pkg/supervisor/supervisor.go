@@ -0,0 +1,175 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+const (
+	StateConnecting   = "connecting"
+	StateConnected    = "connected"
+	StateReconnecting = "reconnecting"
+	StateStopped      = "stopped"
+)
+
+const (
+	defaultMinBackoff  = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+	defaultStableAfter = 10 * time.Second
+)
+
+// RunFunc runs a single stream until it fails or ctx is done. It calls connected once the stream
+// is established.
+type RunFunc func(ctx context.Context, connected func()) error
+
+type Options struct {
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// StableAfter is how long a stream must stay up before the backoff is reset.
+	StableAfter time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = defaultMinBackoff
+	}
+	if o.MaxBackoff < o.MinBackoff {
+		o.MaxBackoff = defaultMaxBackoff
+		if o.MaxBackoff < o.MinBackoff {
+			o.MaxBackoff = o.MinBackoff
+		}
+	}
+	if o.StableAfter <= 0 {
+		o.StableAfter = defaultStableAfter
+	}
+	return o
+}
+
+// Stats is the state of a supervised stream.
+type Stats struct {
+	Name       string    `json:"name"`
+	State      string    `json:"state"`
+	Since      time.Time `json:"since"`
+	Reconnects int64     `json:"reconnects"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Supervisor keeps a stream running, restarting it with an exponential backoff when it fails.
+type Supervisor struct {
+	name      string
+	run       RunFunc
+	opts      Options
+	log       *logger.Logger
+	mu        sync.Mutex
+	stats     Stats
+	callbacks []func(Stats)
+	notifyMu  sync.Mutex
+	done      chan struct{}
+}
+
+func New(name string, run RunFunc, opts Options, log *logger.Logger) *Supervisor {
+	if log == nil {
+		log = logger.NewLogger("supervisor")
+	}
+	return &Supervisor{
+		name: name,
+		run:  run,
+		opts: opts.withDefaults(),
+		log:  log,
+		stats: Stats{
+			Name:  name,
+			State: StateConnecting,
+			Since: time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// Start runs the stream until ctx is done.
+func (s *Supervisor) Start(ctx context.Context) {
+	go s.loop(ctx)
+}
+
+// Done is closed when the supervisor stopped.
+func (s *Supervisor) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Supervisor) Connected() bool {
+	return s.Stats().State == StateConnected
+}
+
+func (s *Supervisor) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// OnState registers a callback called on every state change. It is called with the current
+// state when registered.
+func (s *Supervisor) OnState(fn func(Stats)) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.mu.Lock()
+	s.callbacks = append(s.callbacks, fn)
+	stats := s.stats
+	s.mu.Unlock()
+	fn(stats)
+}
+
+func (s *Supervisor) setState(state string, err error) {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+	s.mu.Lock()
+	if state == StateReconnecting {
+		s.stats.Reconnects++
+	}
+	if err != nil {
+		s.stats.LastError = err.Error()
+	}
+	s.stats.State = state
+	s.stats.Since = time.Now()
+	stats := s.stats
+	callbacks := append([]func(Stats){}, s.callbacks...)
+	s.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(stats)
+	}
+}
+
+func (s *Supervisor) loop(ctx context.Context) {
+	defer close(s.done)
+	backoff := s.opts.MinBackoff
+	for {
+		started := time.Now()
+		err := s.run(ctx, func() {
+			s.setState(StateConnected, nil)
+		})
+		if ctx.Err() != nil {
+			s.setState(StateStopped, nil)
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("stream closed")
+		}
+		if time.Since(started) >= s.opts.StableAfter {
+			backoff = s.opts.MinBackoff
+		}
+		s.setState(StateReconnecting, err)
+		s.log.Errorf("stream %s failed, reconnecting in %s, %s", s.name, backoff, err.Error())
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			s.setState(StateStopped, nil)
+			return
+		}
+		backoff *= 2
+		if backoff > s.opts.MaxBackoff {
+			backoff = s.opts.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type attempt struct {
+	at  time.Time
+	ctx context.Context
+}
+
+func TestSupervisor_Backoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	attempts := make(chan attempt, 10)
+	s := New("test", func(ctx context.Context, connected func()) error {
+		attempts <- attempt{at: time.Now(), ctx: ctx}
+		return fmt.Errorf("failed")
+	}, Options{MinBackoff: 20 * time.Millisecond, MaxBackoff: 80 * time.Millisecond, StableAfter: time.Hour}, nil)
+	s.Start(ctx)
+	var times []time.Time
+	for i := 0; i < 5; i++ {
+		times = append(times, (<-attempts).at)
+	}
+	cancel()
+	<-s.Done()
+	want := []time.Duration{20, 40, 80, 80}
+	for i, delay := range want {
+		require.GreaterOrEqual(t, times[i+1].Sub(times[i]), delay*time.Millisecond)
+	}
+	stats := s.Stats()
+	require.Equal(t, StateStopped, stats.State)
+	require.GreaterOrEqual(t, stats.Reconnects, int64(5))
+	require.Equal(t, "failed", stats.LastError)
+}
+
+func TestSupervisor_StableResetsBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	attempts := make(chan time.Time, 10)
+	count := 0
+	s := New("test", func(ctx context.Context, connected func()) error {
+		attempts <- time.Now()
+		count++
+		if count == 3 {
+			connected()
+			time.Sleep(60 * time.Millisecond)
+		}
+		return fmt.Errorf("failed")
+	}, Options{MinBackoff: 20 * time.Millisecond, MaxBackoff: time.Second, StableAfter: 50 * time.Millisecond}, nil)
+	s.Start(ctx)
+	var times []time.Time
+	for i := 0; i < 4; i++ {
+		times = append(times, <-attempts)
+	}
+	cancel()
+	<-s.Done()
+	// the third attempt stayed up longer than StableAfter, so the backoff after it starts over
+	require.Less(t, times[3].Sub(times[2]), 60*time.Millisecond+40*time.Millisecond)
+}
+
+func TestSupervisor_States(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kill := make(chan struct{})
+	s := New("test", func(ctx context.Context, connected func()) error {
+		connected()
+		select {
+		case <-kill:
+			return fmt.Errorf("killed")
+		case <-ctx.Done():
+			return nil
+		}
+	}, Options{MinBackoff: 10 * time.Millisecond}, nil)
+	mu := sync.Mutex{}
+	var states []string
+	s.OnState(func(stats Stats) {
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, "test", stats.Name)
+		states = append(states, stats.State)
+	})
+	require.False(t, s.Connected())
+	s.Start(ctx)
+	require.Eventually(t, s.Connected, time.Second, time.Millisecond)
+	kill <- struct{}{}
+	require.Eventually(t, func() bool {
+		return s.Connected() && s.Stats().Reconnects == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-s.Done()
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{StateConnecting, StateConnected, StateReconnecting, StateConnected, StateStopped}, states)
+	require.Equal(t, "killed", s.Stats().LastError)
+}
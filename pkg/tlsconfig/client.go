@@ -0,0 +1,58 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+// ClientConfig returns the tls config of a grpc client. The grpc client keeps one config for its
+// lifetime, so the client certificate and the server verification are resolved by callbacks on
+// every handshake, and certificate files changed since the last handshake are reloaded.
+func ClientConfig(opts Options, host string, log *logger.Logger) (*tls.Config, error) {
+	l, err := newLoader(opts, host)
+	if err != nil {
+		return nil, err
+	}
+	current := func() *tls.Config {
+		config, err := l.Config()
+		if err != nil {
+			log.Errorf("error reloading tls certificates, %s", err.Error())
+		}
+		return config
+	}
+	return &tls.Config{
+		ServerName: l.serverName,
+		MinVersion: tls.VersionTLS12,
+		// the server certificate is verified by VerifyConnection with the current root certificates
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			config := current()
+			if len(config.Certificates) == 0 {
+				return &tls.Certificate{}, nil
+			}
+			return &config.Certificates[0], nil
+		},
+		VerifyConnection: func(state tls.ConnectionState) error {
+			config := current()
+			if config.InsecureSkipVerify {
+				return nil
+			}
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("tls server sent no certificates")
+			}
+			verify := x509.VerifyOptions{
+				Roots:         config.RootCAs,
+				DNSName:       l.serverName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range state.PeerCertificates[1:] {
+				verify.Intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(verify)
+			return err
+		},
+	}, nil
+}
@@ -0,0 +1,50 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+)
+
+const dialTimeout = 10 * time.Second
+
+// Dialer dials tls connections for http clients. It reloads the tls config for every new
+// connection when the certificate files change.
+type Dialer struct {
+	loader *loader
+	log    *logger.Logger
+}
+
+func NewDialer(opts Options, log *logger.Logger) (*Dialer, error) {
+	l, err := newLoader(opts, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Dialer{
+		loader: l,
+		log:    log,
+	}, nil
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	config, err := d.loader.Config()
+	if err != nil {
+		d.log.Errorf("error reloading tls certificates, %s", err.Error())
+	}
+	config = config.Clone()
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: dialTimeout},
+		Config:    config,
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,104 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// loader builds the client tls config and rebuilds it when the certificate files change.
+type loader struct {
+	opts       Options
+	serverName string
+	mu         sync.Mutex
+	modTimes   map[string]time.Time
+	config     *tls.Config
+}
+
+func newLoader(opts Options, host string) (*loader, error) {
+	l := &loader{
+		opts:       opts,
+		serverName: opts.ServerName,
+	}
+	if l.serverName == "" {
+		l.serverName = host
+	}
+	if _, err := l.Config(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *loader) files() []string {
+	var list []string
+	for _, file := range []string{l.opts.CAFile, l.opts.CertFile, l.opts.KeyFile} {
+		if file != "" {
+			list = append(list, file)
+		}
+	}
+	return list
+}
+
+// Config returns the current tls config. When a certificate file changed, the config is rebuilt;
+// if the new files cannot be loaded, the error is returned with the last valid config.
+func (l *loader) Config() (*tls.Config, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	modTimes := map[string]time.Time{}
+	changed := l.config == nil
+	for _, file := range l.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return l.config, fmt.Errorf("error reading tls file %s, %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+		if !info.ModTime().Equal(l.modTimes[file]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return l.config, nil
+	}
+	config, err := l.load()
+	if err != nil {
+		return l.config, err
+	}
+	l.config = config
+	l.modTimes = modTimes
+	return l.config, nil
+}
+
+func (l *loader) load() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         l.serverName,
+		InsecureSkipVerify: l.opts.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	caPem := []byte(l.opts.CAPem)
+	if l.opts.CAFile != "" {
+		data, err := ioutil.ReadFile(l.opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading tls ca file, %w", err)
+		}
+		caPem = data
+	}
+	if len(caPem) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("error parsing tls ca certificates")
+		}
+		config.RootCAs = pool
+	}
+	if l.opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(l.opts.CertFile, l.opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading tls client certificate, %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
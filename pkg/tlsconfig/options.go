@@ -0,0 +1,38 @@
+package tlsconfig
+
+import (
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+type Options struct {
+	Enabled            bool
+	CAFile             string
+	CAPem              string
+	ServerName         string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+func ParseOptions(cfg config.Metadata) (Options, error) {
+	o := Options{}
+	o.Enabled = cfg.ParseBool("tls", false)
+	if !o.Enabled {
+		return o, nil
+	}
+	o.CAFile = cfg.ParseString("tls_ca_file", "")
+	o.CAPem = cfg.ParseString("tls_ca_pem", "")
+	if o.CAFile != "" && o.CAPem != "" {
+		return Options{}, fmt.Errorf("error parsing tls options, tls_ca_file and tls_ca_pem cannot be set together")
+	}
+	o.ServerName = cfg.ParseString("tls_server_name", "")
+	o.CertFile = cfg.ParseString("tls_cert_file", "")
+	o.KeyFile = cfg.ParseString("tls_key_file", "")
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return Options{}, fmt.Errorf("error parsing tls options, tls_cert_file and tls_key_file must be set together")
+	}
+	o.InsecureSkipVerify = cfg.ParseBool("tls_insecure_skip_verify", false)
+	return o, nil
+}
@@ -0,0 +1,282 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem []byte
+	keyPem  []byte
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{name},
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPem:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+// startEchoServer starts a tls server echoing the data of every connection.
+func startEchoServer(t *testing.T, server *testCert, clientCA *testCert) (string, int) {
+	cert, err := tls.X509KeyPair(server.certPem, server.keyPem)
+	require.NoError(t, err)
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return host, p
+}
+
+func echo(d *Dialer, host string, port int) error {
+	return echoWith(d.DialContext, host, port)
+}
+
+func echoClient(config *tls.Config, host string, port int) error {
+	dialer := &tls.Dialer{Config: config}
+	return echoWith(dialer.DialContext, host, port)
+}
+
+func echoWith(dial func(ctx context.Context, network, addr string) (net.Conn, error), host string, port int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := dial(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		return err
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return err
+	}
+	if string(buf) != "ping" {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func newDialer(t *testing.T, opts Options) *Dialer {
+	d, err := NewDialer(opts, logger.NewLogger("tls"))
+	require.NoError(t, err)
+	return d
+}
+
+func TestDialer_ServerCA(t *testing.T) {
+	ca := newTestCert(t, "ca", nil, true)
+	server := newTestCert(t, "kubemq.local", ca, false)
+	host, port := startEchoServer(t, server, nil)
+
+	require.NoError(t, echo(newDialer(t, Options{Enabled: true, CAPem: string(ca.certPem), ServerName: "kubemq.local"}), host, port))
+	require.Error(t, echo(newDialer(t, Options{Enabled: true, CAPem: string(ca.certPem), ServerName: "other.local"}), host, port))
+	require.Error(t, echo(newDialer(t, Options{Enabled: true, ServerName: "kubemq.local"}), host, port))
+	require.NoError(t, echo(newDialer(t, Options{Enabled: true, InsecureSkipVerify: true}), host, port))
+}
+
+func newClientConfig(t *testing.T, opts Options) *tls.Config {
+	config, err := ClientConfig(opts, "127.0.0.1", logger.NewLogger("tls"))
+	require.NoError(t, err)
+	return config
+}
+
+func TestClientConfig_ServerCA(t *testing.T) {
+	ca := newTestCert(t, "ca", nil, true)
+	server := newTestCert(t, "kubemq.local", ca, false)
+	host, port := startEchoServer(t, server, nil)
+
+	require.NoError(t, echoClient(newClientConfig(t, Options{Enabled: true, CAPem: string(ca.certPem), ServerName: "kubemq.local"}), host, port))
+	require.Error(t, echoClient(newClientConfig(t, Options{Enabled: true, CAPem: string(ca.certPem), ServerName: "other.local"}), host, port))
+	require.Error(t, echoClient(newClientConfig(t, Options{Enabled: true, CAPem: string(ca.certPem)}), host, port))
+	require.Error(t, echoClient(newClientConfig(t, Options{Enabled: true, ServerName: "kubemq.local"}), host, port))
+	require.NoError(t, echoClient(newClientConfig(t, Options{Enabled: true, InsecureSkipVerify: true}), host, port))
+}
+
+func TestClientConfig_MutualTLSReload(t *testing.T) {
+	ca := newTestCert(t, "ca", nil, true)
+	otherCA := newTestCert(t, "other-ca", nil, true)
+	server := newTestCert(t, "kubemq.local", ca, false)
+	host, port := startEchoServer(t, server, ca)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, caFile, otherCA.certPem, modTime)
+	valid := newTestCert(t, "client", ca, false)
+	writeFile(t, certFile, valid.certPem, modTime)
+	writeFile(t, keyFile, valid.keyPem, modTime)
+
+	// the same config is kept by the grpc client, the files are reloaded on the next handshake
+	config := newClientConfig(t, Options{Enabled: true, CAFile: caFile, ServerName: "kubemq.local", CertFile: certFile, KeyFile: keyFile})
+	require.Error(t, echoClient(config, host, port))
+
+	writeFile(t, caFile, ca.certPem, modTime.Add(time.Second))
+	require.NoError(t, echoClient(config, host, port))
+
+	invalid := newTestCert(t, "client", otherCA, false)
+	writeFile(t, certFile, invalid.certPem, modTime.Add(2*time.Second))
+	writeFile(t, keyFile, invalid.keyPem, modTime.Add(2*time.Second))
+	require.Error(t, echoClient(config, host, port))
+}
+
+func TestDialer_MutualTLSReload(t *testing.T) {
+	ca := newTestCert(t, "ca", nil, true)
+	otherCA := newTestCert(t, "other-ca", nil, true)
+	server := newTestCert(t, "kubemq.local", ca, false)
+	host, port := startEchoServer(t, server, ca)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, caFile, ca.certPem, modTime)
+	invalid := newTestCert(t, "client", otherCA, false)
+	writeFile(t, certFile, invalid.certPem, modTime)
+	writeFile(t, keyFile, invalid.keyPem, modTime)
+
+	d := newDialer(t, Options{Enabled: true, CAFile: caFile, ServerName: "kubemq.local", CertFile: certFile, KeyFile: keyFile})
+	require.Error(t, echo(d, host, port))
+
+	valid := newTestCert(t, "client", ca, false)
+	writeFile(t, certFile, valid.certPem, modTime.Add(time.Second))
+	writeFile(t, keyFile, valid.keyPem, modTime.Add(time.Second))
+	require.NoError(t, echo(d, host, port))
+
+	writeFile(t, keyFile, []byte("broken"), modTime.Add(2*time.Second))
+	require.NoError(t, echo(d, host, port))
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		want    Options
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+			cfg:  config.Metadata{"tls_ca_file": "ca.pem"},
+			want: Options{},
+		},
+		{
+			name: "enabled",
+			cfg: config.Metadata{
+				"tls":                      "true",
+				"tls_ca_file":              "ca.pem",
+				"tls_server_name":          "kubemq.local",
+				"tls_cert_file":            "client.pem",
+				"tls_key_file":             "client.key",
+				"tls_insecure_skip_verify": "true",
+			},
+			want: Options{
+				Enabled:            true,
+				CAFile:             "ca.pem",
+				ServerName:         "kubemq.local",
+				CertFile:           "client.pem",
+				KeyFile:            "client.key",
+				InsecureSkipVerify: true,
+			},
+		},
+		{
+			name:    "ca file and pem",
+			cfg:     config.Metadata{"tls": "true", "tls_ca_file": "ca.pem", "tls_ca_pem": "pem"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"tls": "true", "tls_cert_file": "client.pem"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
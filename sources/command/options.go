@@ -3,7 +3,8 @@ package command
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 	"time"
 )
 
@@ -17,7 +18,9 @@ type options struct {
 	host                     string
 	port                     int
 	clientId                 string
+	sharedConnection         bool
 	authToken                string
+	tls                      tlsconfig.Options
 	channel                  string
 	group                    string
 	autoReconnect            bool
@@ -34,8 +37,13 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
 
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 
 	o.channel, err = cfg.MustParseString("channel")
 	if err != nil {
@@ -58,3 +66,23 @@ func parseOptions(cfg config.Metadata) (options, error) {
 
 	return o, nil
 }
+
+// connection returns the options of the instance-th parallel connection of the source.
+func (o options) connection(instance int) connpool.Options {
+	clientId := o.clientId
+	if o.sources > 1 && clientId != "" {
+		clientId = fmt.Sprintf("%s-%d", clientId, instance)
+	}
+	return connpool.Options{
+		Host:              o.host,
+		Port:              o.port,
+		ClientId:          clientId,
+		AuthToken:         o.authToken,
+		TLS:               o.tls,
+		Exclusive:         !o.sharedConnection,
+		Instance:          instance,
+		AutoReconnect:     o.autoReconnect,
+		ReconnectInterval: o.reconnectIntervalSeconds,
+		MaxReconnects:     o.maxReconnects,
+	}
+}
@@ -3,7 +3,10 @@ package events_store
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
+	"github.com/kubemq-io/kubemq-go"
+	"math"
 	"time"
 )
 
@@ -11,19 +14,52 @@ const (
 	defaultAddress       = "0.0.0.0:50000"
 	defaultAutoReconnect = true
 	defaultSources       = 1
+	defaultConcurrency   = 100
+	defaultQueueSize     = 1000
+
+	defaultCheckpointInterval = 1000
+)
+
+const (
+	startFromNew       = "new"
+	startFromFirst     = "first"
+	startFromLast      = "last"
+	startFromSequence  = "sequence"
+	startFromTime      = "time"
+	startFromTimeDelta = "time_delta"
 )
 
+var startFromMap = map[string]string{
+	"":                 startFromNew,
+	startFromNew:       startFromNew,
+	startFromFirst:     startFromFirst,
+	startFromLast:      startFromLast,
+	startFromSequence:  startFromSequence,
+	startFromTime:      startFromTime,
+	startFromTimeDelta: startFromTimeDelta,
+}
+
 type options struct {
 	host                     string
 	port                     int
 	clientId                 string
+	sharedConnection         bool
 	authToken                string
+	tls                      tlsconfig.Options
 	channel                  string
 	group                    string
 	autoReconnect            bool
 	reconnectIntervalSeconds time.Duration
 	maxReconnects            int
 	sources                  int
+	concurrency              int
+	queueSize                int
+	startFrom                string
+	startSequence            int
+	startTime                time.Time
+	startTimeDelta           time.Duration
+	checkpointFile           string
+	checkpointInterval       time.Duration
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -35,7 +71,12 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	}
 
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.channel, err = cfg.MustParseString("channel")
 	if err != nil {
 		return o, fmt.Errorf("error parsing channel value, %w", err)
@@ -52,5 +93,82 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	}
 	o.reconnectIntervalSeconds = time.Duration(interval) * time.Second
 	o.maxReconnects = cfg.ParseInt("max_reconnects", 0)
+	o.concurrency, err = cfg.ParseIntWithRange("concurrency", defaultConcurrency, 1, 10000)
+	if err != nil {
+		return o, fmt.Errorf("error parsing concurrency value, %w", err)
+	}
+	o.queueSize, err = cfg.ParseIntWithRange("queue_size", defaultQueueSize, 0, 1000000)
+	if err != nil {
+		return o, fmt.Errorf("error parsing queue size value, %w", err)
+	}
+	o.startFrom, err = cfg.ParseStringMap("start_from", startFromMap)
+	if err != nil {
+		return o, fmt.Errorf("error parsing start from value, %w", err)
+	}
+	switch o.startFrom {
+	case startFromSequence:
+		o.startSequence, err = cfg.MustParseIntWithRange("start_from_sequence", 1, math.MaxInt32)
+		if err != nil {
+			return o, fmt.Errorf("error parsing start from sequence value, %w", err)
+		}
+	case startFromTime:
+		value, err := cfg.MustParseString("start_from_time")
+		if err != nil {
+			return o, fmt.Errorf("error parsing start from time value, %w", err)
+		}
+		o.startTime, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return o, fmt.Errorf("error parsing start from time value, %w", err)
+		}
+	case startFromTimeDelta:
+		delta, err := cfg.MustParseIntWithRange("start_from_time_delta_seconds", 1, math.MaxInt32)
+		if err != nil {
+			return o, fmt.Errorf("error parsing start from time delta seconds value, %w", err)
+		}
+		o.startTimeDelta = time.Duration(delta) * time.Second
+	}
+	o.checkpointFile = cfg.ParseString("checkpoint_file", "")
+	checkpointInterval, err := cfg.ParseIntWithRange("checkpoint_interval_milliseconds", defaultCheckpointInterval, 1, math.MaxInt32)
+	if err != nil {
+		return o, fmt.Errorf("error parsing checkpoint interval milliseconds value, %w", err)
+	}
+	o.checkpointInterval = time.Duration(checkpointInterval) * time.Millisecond
 	return o, nil
 }
+
+func (o options) subscriptionOption() kubemq.SubscriptionOption {
+	switch o.startFrom {
+	case startFromFirst:
+		return kubemq.StartFromFirstEvent()
+	case startFromLast:
+		return kubemq.StartFromLastEvent()
+	case startFromSequence:
+		return kubemq.StartFromSequence(o.startSequence)
+	case startFromTime:
+		return kubemq.StartFromTime(o.startTime)
+	case startFromTimeDelta:
+		return kubemq.StartFromTimeDelta(o.startTimeDelta)
+	default:
+		return kubemq.StartFromNewEvents()
+	}
+}
+
+// connection returns the options of the instance-th parallel connection of the source.
+func (o options) connection(instance int) connpool.Options {
+	clientId := o.clientId
+	if o.sources > 1 && clientId != "" {
+		clientId = fmt.Sprintf("%s-%d", clientId, instance)
+	}
+	return connpool.Options{
+		Host:              o.host,
+		Port:              o.port,
+		ClientId:          clientId,
+		AuthToken:         o.authToken,
+		TLS:               o.tls,
+		Exclusive:         !o.sharedConnection,
+		Instance:          instance,
+		AutoReconnect:     o.autoReconnect,
+		ReconnectInterval: o.reconnectIntervalSeconds,
+		MaxReconnects:     o.maxReconnects,
+	}
+}
@@ -0,0 +1,90 @@
+package events_store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_StartFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		want    options
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  config.Metadata{},
+			want: options{startFrom: startFromNew},
+		},
+		{
+			name: "first",
+			cfg:  config.Metadata{"start_from": "first"},
+			want: options{startFrom: startFromFirst},
+		},
+		{
+			name: "last",
+			cfg:  config.Metadata{"start_from": "last"},
+			want: options{startFrom: startFromLast},
+		},
+		{
+			name: "sequence",
+			cfg:  config.Metadata{"start_from": "sequence", "start_from_sequence": "100"},
+			want: options{startFrom: startFromSequence, startSequence: 100},
+		},
+		{
+			name: "time",
+			cfg:  config.Metadata{"start_from": "time", "start_from_time": "2023-01-02T10:00:00Z"},
+			want: options{startFrom: startFromTime, startTime: time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "time delta",
+			cfg:  config.Metadata{"start_from": "time_delta", "start_from_time_delta_seconds": "3600"},
+			want: options{startFrom: startFromTimeDelta, startTimeDelta: time.Hour},
+		},
+		{
+			name:    "invalid - start from",
+			cfg:     config.Metadata{"start_from": "bad"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - no sequence",
+			cfg:     config.Metadata{"start_from": "sequence"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad sequence",
+			cfg:     config.Metadata{"start_from": "sequence", "start_from_sequence": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - bad time",
+			cfg:     config.Metadata{"start_from": "time", "start_from_time": "yesterday"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid - no time delta",
+			cfg:     config.Metadata{"start_from": "time_delta"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg["channel"] = "events-store"
+			got, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want.startFrom, got.startFrom)
+			require.Equal(t, tt.want.startSequence, got.startSequence)
+			require.True(t, tt.want.startTime.Equal(got.startTime))
+			require.Equal(t, tt.want.startTimeDelta, got.startTimeDelta)
+			require.NotNil(t, got.subscriptionOption())
+		})
+	}
+}
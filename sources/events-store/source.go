@@ -5,8 +5,17 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/checkpoint"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/ordering"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
 	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+	"go.uber.org/atomic"
+	"sync"
+	"time"
 
 	"github.com/kubemq-io/kubemq-go"
 
@@ -21,6 +30,16 @@ type Source struct {
 	properties        config.Metadata
 	roundRobin        *roundrobin.RoundRobin
 	loadBalancingMode bool
+	pool              *pool.Pool
+	group             string
+	ordering          ordering.Options
+	checkpoints       *checkpoint.Store
+	checkpoint        checkpoint.Checkpoint
+	checkpointMu      sync.Mutex
+	tracker           *checkpoint.Tracker
+	savedSequence     uint64
+	stopped           bool
+	cancel            context.CancelFunc
 }
 
 func New() *Source {
@@ -38,21 +57,37 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		return err
 	}
 	s.properties = properties
-	for i := 0; i < s.opts.sources; i++ {
-		clientId := s.opts.clientId
+	s.ordering, err = ordering.ParseOptions(properties)
+	if err != nil {
+		return err
+	}
+	if s.ordering.Enabled() {
 		if s.opts.sources > 1 {
-			clientId = fmt.Sprintf("%s-%d", clientId, i)
+			return fmt.Errorf("ordering requires a single source")
+		}
+		s.pool = pool.NewPartitioned(s.opts.channel, s.ordering.Partitions, s.opts.queueSize)
+	} else {
+		s.pool = pool.New(s.opts.channel, s.opts.concurrency, s.opts.queueSize)
+	}
+	if s.opts.checkpointFile != "" {
+		s.checkpoints, err = checkpoint.Open(s.opts.checkpointFile)
+		if err != nil {
+			return fmt.Errorf("error opening checkpoint file, %w", err)
+		}
+		// the key uses the configured group, the random group of parallel sources changes on
+		// every start
+		address := fmt.Sprintf("%s:%d", s.opts.host, s.opts.port)
+		s.checkpoint = checkpoint.Checkpoint{
+			Key:     checkpoint.Key(address, s.opts.channel, s.opts.group),
+			Address: address,
+			Channel: s.opts.channel,
+			Group:   s.opts.group,
 		}
-		client, err := kubemq.NewClient(ctx,
-			kubemq.WithAddress(s.opts.host, s.opts.port),
-			kubemq.WithClientId(clientId),
-			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-			kubemq.WithCheckConnection(true),
-			kubemq.WithAuthToken(s.opts.authToken),
-			kubemq.WithMaxReconnects(s.opts.maxReconnects),
-			kubemq.WithAutoReconnect(s.opts.autoReconnect),
-			kubemq.WithReconnectInterval(s.opts.reconnectIntervalSeconds))
+	}
+	for i := 0; i < s.opts.sources; i++ {
+		client, err := connpool.Client(ctx, s.opts.connection(i))
 		if err != nil {
+			s.release()
 			return err
 		}
 		s.clients = append(s.clients, client)
@@ -61,6 +96,8 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 }
 
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, fmt.Sprintf("%s:%d", s.opts.host, s.opts.port))
+	ctx, s.cancel = context.WithCancel(ctx)
 	s.roundRobin = roundrobin.NewRoundRobin(len(target))
 	if s.properties != nil {
 		mode, ok := s.properties["load-balancing"]
@@ -69,14 +106,27 @@ func (s *Source) Start(ctx context.Context, target []middleware.Middleware) erro
 		}
 	}
 	s.targets = target
+	s.pool.Start(ctx)
 
-	if s.opts.sources > 1 && s.opts.group == "" {
-		s.opts.group = uuid.New().String()
+	s.group = s.opts.group
+	if s.opts.sources > 1 && s.group == "" {
+		s.group = uuid.New().String()
+	}
+	startFrom := s.opts.subscriptionOption()
+	if s.checkpoints != nil {
+		cp, ok := s.checkpoints.Get(s.checkpoint.Key)
+		if ok {
+			startFrom = kubemq.StartFromSequence(int(cp.Sequence + 1))
+			s.log.Infof("resuming channel %s from checkpoint sequence %d", s.opts.channel, cp.Sequence)
+		}
+		s.tracker = checkpoint.NewTracker(cp.Sequence)
+		s.savedSequence = cp.Sequence
+		go s.runCheckpoints(ctx)
 	}
 
 	for _, client := range s.clients {
 		errCh := make(chan error, 1)
-		eventsCh, err := client.SubscribeToEventsStore(ctx, s.opts.channel, s.opts.group, errCh, kubemq.StartFromNewEvents())
+		eventsCh, err := client.SubscribeToEventsStore(ctx, s.opts.channel, s.group, errCh, startFrom)
 		if err != nil {
 			return fmt.Errorf("error on subscribing to events store channel, %w", err)
 		}
@@ -92,24 +142,8 @@ func (s *Source) run(ctx context.Context, eventsCh <-chan *kubemq.EventStoreRece
 	for {
 		select {
 		case event := <-eventsCh:
-
-			if s.loadBalancingMode {
-				go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
-					_, err := target.Do(ctx, event)
-					if err != nil {
-						s.log.Errorf("error received from target, %w", err)
-					}
-				}(event, s.targets[s.roundRobin.Next()])
-			} else {
-				for _, target := range s.targets {
-					go func(event *kubemq.EventStoreReceive, target middleware.Middleware) {
-						_, err := target.Do(ctx, event)
-						if err != nil {
-							s.log.Errorf("error received from target, %w", err)
-						}
-
-					}(event, target)
-				}
+			if err := s.dispatch(ctx, event); err != nil {
+				return
 			}
 		case err := <-errCh:
 			s.log.Errorf("error received from kuebmq server, %s", err.Error())
@@ -121,9 +155,183 @@ func (s *Source) run(ctx context.Context, eventsCh <-chan *kubemq.EventStoreRece
 	}
 }
 
+func (s *Source) dispatch(ctx context.Context, event *kubemq.EventStoreReceive) error {
+	targets := s.targets
+	if s.loadBalancingMode {
+		targets = []middleware.Middleware{s.targets[s.roundRobin.Next()]}
+	}
+	msg, err := message.New(message.SourceEventsStore, event)
+	if err != nil {
+		s.log.Errorf("error parsing event store, %s", err.Error())
+		return nil
+	}
+	d := s.newDelivery(event, len(targets))
+	if s.ordering.Enabled() {
+		return s.submitOrdered(ctx, msg, targets, d)
+	}
+	for _, target := range targets {
+		if err := s.submit(ctx, msg, target, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Source) submit(ctx context.Context, msg *message.Message, target middleware.Middleware, d *delivery) error {
+	return s.pool.Submit(ctx, func() {
+		_, err := target.Do(ctx, msg)
+		if err != nil {
+			s.log.Errorf("error received from target, %s", err.Error())
+		}
+		d.done(err)
+	})
+}
+
+// submitOrdered queues the event on the partition of its ordering key. The targets are called
+// one after another inside the same task, so retries of an event hold back every later event
+// with the same key.
+func (s *Source) submitOrdered(ctx context.Context, msg *message.Message, targets []middleware.Middleware, d *delivery) error {
+	key := s.ordering.PartitionKey(msg)
+	return s.pool.SubmitKey(ctx, key, func() {
+		for _, target := range targets {
+			_, err := target.Do(ctx, msg)
+			if err != nil {
+				s.log.Errorf("error received from target, %s", err.Error())
+			}
+			d.done(err)
+		}
+	})
+}
+
+// delivery reports an event to the checkpoint tracker once all its targets completed.
+type delivery struct {
+	remaining *atomic.Int32
+	failed    *atomic.Bool
+	complete  func(ok bool)
+}
+
+func (s *Source) newDelivery(event *kubemq.EventStoreReceive, targets int) *delivery {
+	d := &delivery{
+		remaining: atomic.NewInt32(int32(targets)),
+		failed:    atomic.NewBool(false),
+		complete:  func(ok bool) {},
+	}
+	if tracker := s.tracker; tracker != nil {
+		tracker.Received(event.Sequence)
+		d.complete = func(ok bool) {
+			tracker.Done(event.Sequence, ok)
+		}
+	}
+	return d
+}
+
+func (d *delivery) done(err error) {
+	if err != nil {
+		d.failed.Store(true)
+	}
+	if d.remaining.Dec() == 0 {
+		d.complete(!d.failed.Load())
+	}
+}
+
+func (s *Source) runCheckpoints(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.saveCheckpoint()
+			s.checkpointMu.Lock()
+			stopped := s.stopped
+			s.checkpointMu.Unlock()
+			if stopped {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Source) saveCheckpoint() {
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	if s.tracker == nil || s.stopped {
+		return
+	}
+	sequence := s.tracker.Sequence()
+	if sequence == s.savedSequence {
+		return
+	}
+	cp := s.checkpoint
+	cp.Sequence = sequence
+	if err := s.checkpoints.Set(cp); err != nil {
+		s.log.Errorf("error saving checkpoint, %s", err.Error())
+		return
+	}
+	s.savedSequence = sequence
+}
+
+// Checkpoint returns the current checkpoint and whether checkpointing is enabled.
+func (s *Source) Checkpoint() (checkpoint.Checkpoint, bool) {
+	if s.checkpoints == nil {
+		return checkpoint.Checkpoint{}, false
+	}
+	cp, ok := s.checkpoints.Get(s.checkpoint.Key)
+	if !ok {
+		cp = s.checkpoint
+	}
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	if s.tracker != nil && !s.stopped {
+		cp.Sequence = s.tracker.Sequence()
+	}
+	return cp, true
+}
+
+// Rewind sets the checkpoint so the next start resumes from sequence. The source must be stopped.
+func (s *Source) Rewind(sequence uint64) error {
+	if s.checkpoints == nil {
+		return fmt.Errorf("checkpoint is not enabled")
+	}
+	if sequence < 1 {
+		return fmt.Errorf("invalid sequence %d", sequence)
+	}
+	cp := s.checkpoint
+	cp.Sequence = sequence - 1
+	return s.checkpoints.Set(cp)
+}
+
+// ResetCheckpoint deletes the checkpoint so the next start uses start_from. The source must be stopped.
+func (s *Source) ResetCheckpoint() error {
+	if s.checkpoints == nil {
+		return fmt.Errorf("checkpoint is not enabled")
+	}
+	return s.checkpoints.Delete(s.checkpoint.Key)
+}
+
 func (s *Source) Stop() error {
-	for _, client := range s.clients {
-		_ = client.Close()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.release()
+	s.pool.Stop()
+	if s.checkpoints != nil {
+		s.saveCheckpoint()
+		s.checkpointMu.Lock()
+		s.stopped = true
+		s.checkpointMu.Unlock()
 	}
 	return nil
 }
+
+func (s *Source) release() {
+	for _, client := range s.clients {
+		_ = connpool.Release(client)
+	}
+	s.clients = nil
+}
+
+func (s *Source) Pools() []*pool.Pool {
+	return []*pool.Pool{s.pool}
+}
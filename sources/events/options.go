@@ -3,7 +3,8 @@ package events
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 	"time"
 )
 
@@ -11,19 +12,25 @@ const (
 	defaultAddress       = "0.0.0.0:50000"
 	defaultAutoReconnect = true
 	defaultSources       = 1
+	defaultConcurrency   = 100
+	defaultQueueSize     = 1000
 )
 
 type options struct {
 	host                     string
 	port                     int
 	clientId                 string
+	sharedConnection         bool
 	authToken                string
+	tls                      tlsconfig.Options
 	channel                  string
 	group                    string
 	autoReconnect            bool
 	reconnectIntervalSeconds time.Duration
 	maxReconnects            int
 	sources                  int
+	concurrency              int
+	queueSize                int
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -35,7 +42,12 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	}
 
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.channel, err = cfg.MustParseString("channel")
 	if err != nil {
 		return o, fmt.Errorf("error parsing channel value, %w", err)
@@ -52,5 +64,33 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	}
 	o.reconnectIntervalSeconds = time.Duration(interval) * time.Second
 	o.maxReconnects = cfg.ParseInt("max_reconnects", 0)
+	o.concurrency, err = cfg.ParseIntWithRange("concurrency", defaultConcurrency, 1, 10000)
+	if err != nil {
+		return o, fmt.Errorf("error parsing concurrency value, %w", err)
+	}
+	o.queueSize, err = cfg.ParseIntWithRange("queue_size", defaultQueueSize, 0, 1000000)
+	if err != nil {
+		return o, fmt.Errorf("error parsing queue size value, %w", err)
+	}
 	return o, nil
 }
+
+// connection returns the options of the instance-th parallel connection of the source.
+func (o options) connection(instance int) connpool.Options {
+	clientId := o.clientId
+	if o.sources > 1 && clientId != "" {
+		clientId = fmt.Sprintf("%s-%d", clientId, instance)
+	}
+	return connpool.Options{
+		Host:              o.host,
+		Port:              o.port,
+		ClientId:          clientId,
+		AuthToken:         o.authToken,
+		TLS:               o.tls,
+		Exclusive:         !o.sharedConnection,
+		Instance:          instance,
+		AutoReconnect:     o.autoReconnect,
+		ReconnectInterval: o.reconnectIntervalSeconds,
+		MaxReconnects:     o.maxReconnects,
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
 	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
 
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
@@ -20,6 +24,8 @@ type Source struct {
 	properties        config.Metadata
 	roundRobin        *roundrobin.RoundRobin
 	loadBalancingMode bool
+	pool              *pool.Pool
+	cancel            context.CancelFunc
 }
 
 func New() *Source {
@@ -37,21 +43,11 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		return err
 	}
 	s.properties = properties
+	s.pool = pool.New(s.opts.channel, s.opts.concurrency, s.opts.queueSize)
 	for i := 0; i < s.opts.sources; i++ {
-		clientId := s.opts.clientId
-		if s.opts.sources > 1 {
-			clientId = fmt.Sprintf("%s-%d", clientId, i)
-		}
-		client, err := kubemq.NewClient(ctx,
-			kubemq.WithAddress(s.opts.host, s.opts.port),
-			kubemq.WithClientId(clientId),
-			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-			kubemq.WithCheckConnection(true),
-			kubemq.WithAuthToken(s.opts.authToken),
-			kubemq.WithMaxReconnects(s.opts.maxReconnects),
-			kubemq.WithAutoReconnect(s.opts.autoReconnect),
-			kubemq.WithReconnectInterval(s.opts.reconnectIntervalSeconds))
+		client, err := connpool.Client(ctx, s.opts.connection(i))
 		if err != nil {
+			s.release()
 			return err
 		}
 		s.clients = append(s.clients, client)
@@ -60,6 +56,8 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 }
 
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, fmt.Sprintf("%s:%d", s.opts.host, s.opts.port))
+	ctx, s.cancel = context.WithCancel(ctx)
 	s.roundRobin = roundrobin.NewRoundRobin(len(target))
 	if s.properties != nil {
 		mode, ok := s.properties["load-balancing"]
@@ -68,6 +66,7 @@ func (s *Source) Start(ctx context.Context, target []middleware.Middleware) erro
 		}
 	}
 	s.targets = target
+	s.pool.Start(ctx)
 	if s.opts.sources > 1 && s.opts.group == "" {
 		s.opts.group = uuid.New().String()
 	}
@@ -90,24 +89,22 @@ func (s *Source) run(ctx context.Context, eventsCh <-chan *kubemq.Event, errCh c
 	for {
 		select {
 		case event := <-eventsCh:
+			msg, err := message.New(message.SourceEvents, event)
+			if err != nil {
+				s.log.Errorf("error parsing event, %s", err.Error())
+				continue
+			}
 			if s.loadBalancingMode {
-				go func(event *kubemq.Event, target middleware.Middleware) {
-					_, err := target.Do(ctx, event)
-					if err != nil {
-						s.log.Errorf("error received from target, %s", err.Error())
-					}
-				}(event, s.targets[s.roundRobin.Next()])
+				if err := s.submit(ctx, msg, s.targets[s.roundRobin.Next()]); err != nil {
+					return
+				}
 			} else {
 				for _, target := range s.targets {
-					go func(event *kubemq.Event, target middleware.Middleware) {
-						_, err := target.Do(ctx, event)
-						if err != nil {
-							s.log.Errorf("error received from target, %s", err.Error())
-						}
-					}(event, target)
+					if err := s.submit(ctx, msg, target); err != nil {
+						return
+					}
 				}
 			}
-
 		case err := <-errCh:
 			s.log.Errorf("error received from kuebmq server, %s", err.Error())
 			return
@@ -118,9 +115,31 @@ func (s *Source) run(ctx context.Context, eventsCh <-chan *kubemq.Event, errCh c
 	}
 }
 
+func (s *Source) submit(ctx context.Context, msg *message.Message, target middleware.Middleware) error {
+	return s.pool.Submit(ctx, func() {
+		_, err := target.Do(ctx, msg)
+		if err != nil {
+			s.log.Errorf("error received from target, %s", err.Error())
+		}
+	})
+}
+
 func (s *Source) Stop() error {
-	for _, client := range s.clients {
-		_ = client.Close()
+	if s.cancel != nil {
+		s.cancel()
 	}
+	s.release()
+	s.pool.Stop()
 	return nil
 }
+
+func (s *Source) release() {
+	for _, client := range s.clients {
+		_ = connpool.Release(client)
+	}
+	s.clients = nil
+}
+
+func (s *Source) Pools() []*pool.Pool {
+	return []*pool.Pool{s.pool}
+}
@@ -0,0 +1,58 @@
+package file
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	speedMax                  = "max"
+	speedOriginal             = "original"
+	speedRate                 = "rate"
+	defaultMessagesPerSecond  = 100
+	defaultCheckpointInterval = 1000
+)
+
+var speeds = map[string]string{
+	"":            speedMax,
+	speedMax:      speedMax,
+	speedOriginal: speedOriginal,
+	speedRate:     speedRate,
+}
+
+type options struct {
+	path               string
+	channel            string
+	speed              string
+	messagesPerSecond  int
+	checkpointFile     string
+	checkpointInterval time.Duration
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.path, err = cfg.MustParseString("path")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing path value, %w", err)
+	}
+	o.channel = cfg.ParseString("channel", "")
+	o.speed, err = cfg.ParseStringMap("speed", speeds)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing speed value, %w", err)
+	}
+	o.messagesPerSecond, err = cfg.ParseIntWithRange("messages_per_second", defaultMessagesPerSecond, 1, 1000000)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing messages per second value, %w", err)
+	}
+	o.checkpointFile = cfg.ParseString("checkpoint_file", "")
+	checkpointInterval, err := cfg.ParseIntWithRange("checkpoint_interval_milliseconds", defaultCheckpointInterval, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing checkpoint interval milliseconds value, %w", err)
+	}
+	o.checkpointInterval = time.Duration(checkpointInterval) * time.Millisecond
+	return o, nil
+}
@@ -0,0 +1,324 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/archive"
+	"github.com/kubemq-io/kubemq-bridges/pkg/checkpoint"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+	"github.com/kubemq-io/kubemq-bridges/pkg/ratelimit"
+	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+)
+
+// Source replays the messages of archive files written by the file target. The files of each dir
+// are read in order and the dirs are merged by message timestamp. The checkpoint is the last line
+// replayed per file, with the number of messages replayed as its sequence.
+type Source struct {
+	opts              options
+	log               *logger.Logger
+	targets           []middleware.Middleware
+	properties        config.Metadata
+	roundRobin        *roundrobin.RoundRobin
+	loadBalancingMode bool
+	files             []string
+	checkpoints       *checkpoint.Store
+	checkpoint        checkpoint.Checkpoint
+	checkpointMu      sync.Mutex
+	sequence          uint64
+	positions         map[string]uint64
+	savedSequence     uint64
+	stopped           bool
+	cancel            context.CancelFunc
+	done              chan struct{}
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("file")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.files, err = archive.Files(s.opts.path)
+	if err != nil {
+		return fmt.Errorf("error listing archive files, %w", err)
+	}
+	if len(s.files) == 0 {
+		return fmt.Errorf("no archive files found in %s", s.opts.path)
+	}
+	if s.opts.checkpointFile != "" {
+		s.checkpoints, err = checkpoint.Open(s.opts.checkpointFile)
+		if err != nil {
+			return fmt.Errorf("error opening checkpoint file, %w", err)
+		}
+		s.checkpoint = checkpoint.Checkpoint{
+			Key:     checkpoint.Key("file", s.opts.path, ""),
+			Address: "file",
+			Channel: s.opts.path,
+		}
+	}
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, s.opts.path)
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.roundRobin = roundrobin.NewRoundRobin(len(target))
+	if s.properties != nil {
+		mode, ok := s.properties["load-balancing"]
+		if ok && mode == "true" {
+			s.loadBalancingMode = true
+		}
+	}
+	s.targets = target
+	var cp checkpoint.Checkpoint
+	if s.checkpoints != nil {
+		var ok bool
+		if cp, ok = s.checkpoints.Get(s.checkpoint.Key); ok {
+			s.log.Infof("resuming replay of %s after message %d", s.opts.path, cp.Sequence)
+		}
+		go s.runCheckpoints(ctx)
+	}
+	s.checkpointMu.Lock()
+	s.sequence = cp.Sequence
+	s.savedSequence = cp.Sequence
+	s.positions = copyPositions(cp.Files)
+	s.checkpointMu.Unlock()
+	s.done = make(chan struct{})
+	go s.run(ctx, archive.NewMerger(s.files, cp.Files))
+	return nil
+}
+
+func (s *Source) run(ctx context.Context, merger *archive.Merger) {
+	defer close(s.done)
+	defer merger.Close()
+	pacer := s.newPacer()
+	var count uint64
+	for ctx.Err() == nil {
+		record, position, err := merger.Next()
+		if err == io.EOF {
+			s.saveCheckpoint()
+			s.log.Infof("replay of %s completed, %d messages", s.opts.path, count)
+			return
+		}
+		count++
+		if err != nil {
+			s.log.Errorf("error reading %s line %d, %s", position.File, position.Line, err.Error())
+			s.advance(position)
+			continue
+		}
+		if !pacer.wait(ctx, record.Timestamp) {
+			return
+		}
+		msg, err := message.New(message.SourceFile, record.Message())
+		if err != nil {
+			s.log.Errorf("error parsing %s line %d, %s", position.File, position.Line, err.Error())
+			s.advance(position)
+			continue
+		}
+		if s.opts.channel != "" {
+			msg.Channel = s.opts.channel
+		}
+		s.send(ctx, msg)
+		if ctx.Err() != nil {
+			// the message may not have reached the targets, it is replayed on the next start
+			return
+		}
+		s.advance(position)
+	}
+}
+
+// advance records a line as replayed.
+func (s *Source) advance(position archive.Position) {
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	s.sequence++
+	if position.Line > s.positions[position.File] {
+		s.positions[position.File] = position.Line
+	}
+}
+
+func copyPositions(positions map[string]uint64) map[string]uint64 {
+	result := map[string]uint64{}
+	for file, line := range positions {
+		result[file] = line
+	}
+	return result
+}
+
+// send passes the message to the targets. A failed message is logged and the replay goes on, as
+// the retry middleware already retried it.
+func (s *Source) send(ctx context.Context, msg *message.Message) {
+	targets := s.targets
+	if s.loadBalancingMode {
+		targets = []middleware.Middleware{s.targets[s.roundRobin.Next()]}
+	}
+	for _, target := range targets {
+		if _, err := target.Do(ctx, msg); err != nil {
+			s.log.Errorf("error received from target, %s", err.Error())
+		}
+	}
+}
+
+type pacer struct {
+	speed   string
+	limiter ratelimit.Limiter
+	first   time.Time
+	started time.Time
+}
+
+func (s *Source) newPacer() *pacer {
+	p := &pacer{
+		speed: s.opts.speed,
+	}
+	if p.speed == speedRate {
+		p.limiter = ratelimit.New(s.opts.messagesPerSecond)
+	}
+	return p
+}
+
+// wait holds the next message according to the replay speed. It returns false when ctx is done.
+func (p *pacer) wait(ctx context.Context, timestamp time.Time) bool {
+	switch p.speed {
+	case speedRate:
+		p.limiter.Take()
+	case speedOriginal:
+		if p.first.IsZero() {
+			p.first = timestamp
+			p.started = time.Now()
+			break
+		}
+		delay := timestamp.Sub(p.first) - time.Since(p.started)
+		if delay <= 0 {
+			break
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return ctx.Err() == nil
+}
+
+func (s *Source) runCheckpoints(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.checkpointInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.saveCheckpoint()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Source) saveCheckpoint() {
+	if s.checkpoints == nil {
+		return
+	}
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	if s.stopped || s.sequence == s.savedSequence {
+		return
+	}
+	cp := s.checkpoint
+	cp.Sequence = s.sequence
+	cp.Files = copyPositions(s.positions)
+	if err := s.checkpoints.Set(cp); err != nil {
+		s.log.Errorf("error saving checkpoint, %s", err.Error())
+		return
+	}
+	s.savedSequence = s.sequence
+}
+
+// Checkpoint returns the current checkpoint and whether checkpointing is enabled.
+func (s *Source) Checkpoint() (checkpoint.Checkpoint, bool) {
+	if s.checkpoints == nil {
+		return checkpoint.Checkpoint{}, false
+	}
+	cp, ok := s.checkpoints.Get(s.checkpoint.Key)
+	if !ok {
+		cp = s.checkpoint
+	}
+	s.checkpointMu.Lock()
+	defer s.checkpointMu.Unlock()
+	if s.done != nil && !s.stopped {
+		cp.Sequence = s.sequence
+		cp.Files = copyPositions(s.positions)
+	}
+	return cp, true
+}
+
+// Rewind sets the checkpoint so the next start resumes from message sequence of a replay from the
+// beginning of the current files. The source must be stopped.
+func (s *Source) Rewind(sequence uint64) error {
+	if s.checkpoints == nil {
+		return fmt.Errorf("checkpoint is not enabled")
+	}
+	if sequence < 1 {
+		return fmt.Errorf("invalid sequence %d", sequence)
+	}
+	files, err := archive.Files(s.opts.path)
+	if err != nil {
+		return fmt.Errorf("error listing archive files, %w", err)
+	}
+	merger := archive.NewMerger(files, nil)
+	defer merger.Close()
+	positions := map[string]uint64{}
+	for i := uint64(1); i < sequence; i++ {
+		_, position, err := merger.Next()
+		if err == io.EOF {
+			return fmt.Errorf("invalid sequence %d, the files have %d messages", sequence, i-1)
+		}
+		if position.Line > positions[position.File] {
+			positions[position.File] = position.Line
+		}
+	}
+	cp := s.checkpoint
+	cp.Sequence = sequence - 1
+	cp.Files = positions
+	return s.checkpoints.Set(cp)
+}
+
+// ResetCheckpoint deletes the checkpoint so the next start replays the files from the beginning.
+// The source must be stopped.
+func (s *Source) ResetCheckpoint() error {
+	if s.checkpoints == nil {
+		return fmt.Errorf("checkpoint is not enabled")
+	}
+	return s.checkpoints.Delete(s.checkpoint.Key)
+}
+
+func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.done != nil {
+		<-s.done
+	}
+	s.saveCheckpoint()
+	s.checkpointMu.Lock()
+	s.stopped = true
+	s.checkpointMu.Unlock()
+	return nil
+}
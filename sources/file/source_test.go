@@ -0,0 +1,262 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/archive"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []*message.Message
+	times    []time.Time
+}
+
+func (r *recorder) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, request.(*message.Message))
+	r.times = append(r.times, time.Now())
+	return nil, nil
+}
+
+func (r *recorder) ids() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, msg := range r.messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+func writeArchive(t *testing.T, count int, interval time.Duration) string {
+	dir := t.TempDir()
+	var ids []string
+	for i := 1; i <= count; i++ {
+		ids = append(ids, fmt.Sprintf("%d", i))
+	}
+	appendArchive(t, dir, "orders", ids, time.Now().Add(-time.Hour), interval)
+	return dir
+}
+
+func appendArchive(t *testing.T, dir, channel string, ids []string, start time.Time, interval time.Duration) {
+	w, err := archive.NewWriter(archive.WriterOptions{Dir: dir, Partition: archive.PartitionChannel, MaxFileSize: 1024})
+	require.NoError(t, err)
+	for i, id := range ids {
+		require.NoError(t, w.Write(archive.NewRecord(&message.Message{
+			ID:        id,
+			Channel:   channel,
+			Body:      []byte("body"),
+			Tags:      map[string]string{"key": "value"},
+			Timestamp: start.Add(time.Duration(i) * interval),
+		})))
+	}
+	require.NoError(t, w.Close())
+}
+
+func runSource(t *testing.T, connection config.Metadata, properties config.Metadata, targets ...middleware.Middleware) *Source {
+	s := New()
+	require.NoError(t, s.Init(context.Background(), connection, properties, nil))
+	require.NoError(t, s.Start(context.Background(), targets))
+	t.Cleanup(func() {
+		_ = s.Stop()
+	})
+	return s
+}
+
+func waitDone(t *testing.T, s *Source) {
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "replay not completed")
+	}
+}
+
+func TestSource_Replay(t *testing.T) {
+	dir := writeArchive(t, 20, time.Second)
+	files, err := archive.Files(dir)
+	require.NoError(t, err)
+	require.Greater(t, len(files), 1)
+
+	r := &recorder{}
+	s := runSource(t, config.Metadata{"path": dir, "channel": "replayed"}, nil, r)
+	waitDone(t, s)
+	var want []string
+	for i := 1; i <= 20; i++ {
+		want = append(want, fmt.Sprintf("%d", i))
+	}
+	require.Equal(t, want, r.ids())
+	msg := r.messages[0]
+	require.Equal(t, "replayed", msg.Channel)
+	require.Equal(t, message.SourceFile, msg.Source)
+	require.Equal(t, []byte("body"), msg.Body)
+	require.Equal(t, "value", msg.Tags["key"])
+	require.True(t, msg.Timestamp.Before(time.Now().Add(-30*time.Minute)))
+}
+
+func TestSource_LoadBalancing(t *testing.T) {
+	dir := writeArchive(t, 4, time.Second)
+	first, second := &recorder{}, &recorder{}
+	s := runSource(t, config.Metadata{"path": dir}, config.Metadata{"load-balancing": "true"}, first, second)
+	waitDone(t, s)
+	require.Equal(t, []string{"1", "3"}, first.ids())
+	require.Equal(t, []string{"2", "4"}, second.ids())
+}
+
+func TestSource_Speed(t *testing.T) {
+	dir := writeArchive(t, 5, 100*time.Millisecond)
+
+	r := &recorder{}
+	s := runSource(t, config.Metadata{"path": dir, "speed": "original"}, nil, r)
+	waitDone(t, s)
+	require.Len(t, r.times, 5)
+	require.GreaterOrEqual(t, r.times[4].Sub(r.times[0]), 400*time.Millisecond)
+
+	r = &recorder{}
+	s = runSource(t, config.Metadata{"path": dir, "speed": "rate", "messages_per_second": "20"}, nil, r)
+	waitDone(t, s)
+	require.Len(t, r.times, 5)
+	require.GreaterOrEqual(t, r.times[4].Sub(r.times[0]), 150*time.Millisecond)
+}
+
+func TestSource_Checkpoint(t *testing.T) {
+	dir := writeArchive(t, 10, time.Second)
+	connection := config.Metadata{
+		"path":            dir,
+		"checkpoint_file": filepath.Join(t.TempDir(), "checkpoints.json"),
+	}
+
+	// stop the replay after the fourth message
+	r := &recorder{}
+	var s *Source
+	stopAt := make(chan struct{})
+	target := middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		_, _ = r.Do(ctx, request)
+		if len(r.ids()) == 4 {
+			close(stopAt)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return nil, nil
+	})
+	s = runSource(t, connection, nil, target)
+	<-stopAt
+	require.NoError(t, s.Stop())
+	cp, enabled := s.Checkpoint()
+	require.True(t, enabled)
+	require.Equal(t, uint64(3), cp.Sequence)
+
+	// the fourth message did not complete, so the replay resumes from it
+	r = &recorder{}
+	s = runSource(t, connection, nil, r)
+	waitDone(t, s)
+	require.Equal(t, []string{"4", "5", "6", "7", "8", "9", "10"}, r.ids())
+	require.NoError(t, s.Stop())
+	cp, _ = s.Checkpoint()
+	require.Equal(t, uint64(10), cp.Sequence)
+
+	require.NoError(t, s.Rewind(9))
+	r = &recorder{}
+	s = runSource(t, connection, nil, r)
+	waitDone(t, s)
+	require.Equal(t, []string{"9", "10"}, r.ids())
+	require.NoError(t, s.Stop())
+
+	require.NoError(t, s.ResetCheckpoint())
+	r = &recorder{}
+	s = runSource(t, connection, nil, r)
+	waitDone(t, s)
+	require.Len(t, r.ids(), 10)
+}
+
+func TestSource_MergeChannels(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	appendArchive(t, dir, "orders", []string{"o1", "o2", "o3"}, start, 2*time.Second)
+	appendArchive(t, dir, "alerts", []string{"a1", "a2", "a3"}, start.Add(time.Second), 2*time.Second)
+
+	r := &recorder{}
+	s := runSource(t, config.Metadata{"path": dir}, nil, r)
+	waitDone(t, s)
+	require.Equal(t, []string{"o1", "a1", "o2", "a2", "o3", "a3"}, r.ids())
+}
+
+func TestSource_CheckpointNewFiles(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	appendArchive(t, dir, "orders", []string{"o1", "o2"}, start, time.Second)
+	connection := config.Metadata{
+		"path":            dir,
+		"checkpoint_file": filepath.Join(t.TempDir(), "checkpoints.json"),
+	}
+	r := &recorder{}
+	s := runSource(t, connection, nil, r)
+	waitDone(t, s)
+	require.NoError(t, s.Stop())
+	require.Equal(t, []string{"o1", "o2"}, r.ids())
+
+	// a new partition sorting before the replayed one and new messages of the replayed one
+	appendArchive(t, dir, "alerts", []string{"a1"}, start.Add(time.Minute), time.Second)
+	appendArchive(t, dir, "orders", []string{"o3"}, start.Add(2*time.Minute), time.Second)
+	r = &recorder{}
+	s = runSource(t, connection, nil, r)
+	waitDone(t, s)
+	require.NoError(t, s.Stop())
+	require.Equal(t, []string{"a1", "o3"}, r.ids())
+	cp, _ := s.Checkpoint()
+	require.Equal(t, uint64(4), cp.Sequence)
+	require.Len(t, cp.Files, 2)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  config.Metadata{"path": "archive/*.jsonl", "speed": "rate", "messages_per_second": "10"},
+		},
+		{
+			name:    "no path",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "bad speed",
+			cfg:     config.Metadata{"path": "archive", "speed": "slow"},
+			wantErr: true,
+		},
+		{
+			name:    "bad messages per second",
+			cfg:     config.Metadata{"path": "archive", "messages_per_second": "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSource_NoFiles(t *testing.T) {
+	s := New()
+	require.Error(t, s.Init(context.Background(), config.Metadata{"path": t.TempDir()}, nil, nil))
+}
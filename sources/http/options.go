@@ -0,0 +1,98 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+)
+
+const (
+	defaultAddress     = "0.0.0.0:8080"
+	defaultPath        = "/"
+	defaultMethod      = "POST"
+	defaultMaxBodySize = 4 * 1024 * 1024
+	defaultTimeout     = 30
+	authTypeNone       = "none"
+	authTypeBasic      = "basic"
+	authTypeBearer     = "bearer"
+)
+
+var authTypes = map[string]string{
+	"":             authTypeNone,
+	authTypeNone:   authTypeNone,
+	authTypeBasic:  authTypeBasic,
+	authTypeBearer: authTypeBearer,
+}
+
+type options struct {
+	address     string
+	path        string
+	methods     map[string]bool
+	channel     string
+	authType    string
+	username    string
+	password    string
+	bearerToken string
+	certFile    string
+	keyFile     string
+	maxBodySize int64
+	timeout     time.Duration
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.address = cfg.ParseString("address", defaultAddress)
+	if _, _, err := net.SplitHostPort(o.address); err != nil {
+		return options{}, fmt.Errorf("error parsing address value, %w", err)
+	}
+	o.path = cfg.ParseString("path", defaultPath)
+	if !strings.HasPrefix(o.path, "/") {
+		return options{}, fmt.Errorf("error parsing path value, path must start with /")
+	}
+	o.methods = map[string]bool{}
+	methods := cfg.ParseStringList("methods")
+	if len(methods) == 0 {
+		methods = []string{defaultMethod}
+	}
+	for _, method := range methods {
+		o.methods[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+	o.channel = cfg.ParseString("channel", "")
+	o.authType, err = cfg.ParseStringMap("auth_type", authTypes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing auth type value, %w", err)
+	}
+	switch o.authType {
+	case authTypeBasic:
+		o.username, err = cfg.MustParseString("username")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing username value, %w", err)
+		}
+		o.password = cfg.ParseString("password", "")
+	case authTypeBearer:
+		o.bearerToken, err = cfg.MustParseString("bearer_token")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing bearer token value, %w", err)
+		}
+	}
+	o.certFile = cfg.ParseString("tls_cert_file", "")
+	o.keyFile = cfg.ParseString("tls_key_file", "")
+	if (o.certFile == "") != (o.keyFile == "") {
+		return options{}, fmt.Errorf("error parsing tls options, tls_cert_file and tls_key_file must be set together")
+	}
+	maxBodySize, err := cfg.ParseIntWithRange("max_body_size", defaultMaxBodySize, 1, 100*1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max body size value, %w", err)
+	}
+	o.maxBodySize = int64(maxBodySize)
+	timeout, err := cfg.ParseIntWithRange("timeout_seconds", defaultTimeout, 1, 3600)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	o.timeout = time.Duration(timeout) * time.Second
+	return o, nil
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+const (
+	headerRequestId = "X-Request-Id"
+	headerMetadata  = "X-Kubemq-Metadata"
+	headerTagPrefix = "X-Kubemq-Tag-"
+	shutdownTimeout = 5 * time.Second
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// credentialHeaders are not passed as tags, so the webhook credentials do not reach the targets.
+var credentialHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+type Source struct {
+	opts              options
+	log               *logger.Logger
+	targets           []middleware.Middleware
+	properties        config.Metadata
+	roundRobin        *roundrobin.RoundRobin
+	loadBalancingMode bool
+	gather            *middleware.ScatterGatherMiddleware
+	listener          net.Listener
+	server            *http.Server
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("http")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.gather, err = middleware.NewScatterGatherMiddleware(properties)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", s.opts.address)
+	if err != nil {
+		return fmt.Errorf("error listening on %s, %w", s.opts.address, err)
+	}
+	if s.opts.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.opts.certFile, s.opts.keyFile)
+		if err != nil {
+			_ = listener.Close()
+			return fmt.Errorf("error loading tls certificate, %w", err)
+		}
+		listener = tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		})
+	}
+	s.listener = listener
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, s.listener.Addr().String())
+	s.roundRobin = roundrobin.NewRoundRobin(len(target))
+	if s.properties != nil {
+		mode, ok := s.properties["load-balancing"]
+		if ok && mode == "true" {
+			s.loadBalancingMode = true
+		}
+	}
+	if s.gather.IsActive() {
+		if err := s.gather.Validate(len(target)); err != nil {
+			return err
+		}
+		target = []middleware.Middleware{middleware.ScatterGather(s.gather, target)}
+		s.loadBalancingMode = false
+	}
+	s.targets = target
+	mux := http.NewServeMux()
+	mux.Handle(s.opts.path, s.handler(ctx))
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			s.log.Errorf("error serving http source on %s, %s", s.opts.address, err.Error())
+		}
+	}()
+	return nil
+}
+
+func (s *Source) handler(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.opts.methods[r.Method] {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		if !s.authorized(r) {
+			if s.opts.authType == authTypeBasic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="kubemq-bridges"`)
+			}
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.opts.maxBodySize))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", s.opts.maxBodySize))
+				return
+			}
+			writeError(w, http.StatusBadRequest, fmt.Errorf("error reading request body, %w", err))
+			return
+		}
+		msg, err := message.New(message.SourceHTTP, s.parseRequest(r, body))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, s.opts.timeout)
+		defer cancel()
+		result, err := s.send(sendCtx, msg)
+		if err != nil {
+			s.log.Errorf("error received from target, %s", err.Error())
+			if sendCtx.Err() == context.DeadlineExceeded {
+				writeError(w, http.StatusGatewayTimeout, err)
+				return
+			}
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		writeResult(w, msg, result)
+	}
+}
+
+func (s *Source) authorized(r *http.Request) bool {
+	switch s.opts.authType {
+	case authTypeBasic:
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			return false
+		}
+		usernameOk := subtle.ConstantTimeCompare([]byte(username), []byte(s.opts.username)) == 1
+		passwordOk := subtle.ConstantTimeCompare([]byte(password), []byte(s.opts.password)) == 1
+		return usernameOk && passwordOk
+	case authTypeBearer:
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return false
+		}
+		token := strings.TrimPrefix(header, "Bearer ")
+		return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.bearerToken)) == 1
+	default:
+		return true
+	}
+}
+
+// parseRequest maps the request to a message. Headers and query params are passed as tags
+// prefixed with "header." and "query.", multiple values are joined with a comma. Credential
+// headers are dropped.
+func (s *Source) parseRequest(r *http.Request, body []byte) *message.Message {
+	msg := &message.Message{
+		ID:       r.Header.Get(headerRequestId),
+		Channel:  s.opts.channel,
+		Metadata: r.Header.Get(headerMetadata),
+		Body:     body,
+		Tags: map[string]string{
+			"http.method": r.Method,
+			"http.path":   r.URL.Path,
+		},
+	}
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+	for key, values := range r.Header {
+		if credentialHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		msg.Tags["header."+strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	for key, values := range r.URL.Query() {
+		msg.Tags["query."+key] = strings.Join(values, ",")
+	}
+	return msg
+}
+
+// send passes the message to the targets of the binding and returns the result of the first
+// one. In load balancing mode the message is sent to a single target.
+func (s *Source) send(ctx context.Context, msg *message.Message) (interface{}, error) {
+	if s.loadBalancingMode {
+		return s.targets[s.roundRobin.Next()].Do(ctx, msg)
+	}
+	// the reply is the first target's result, skipping targets whose middleware
+	// dropped the message; the request is reported as dropped only if all did
+	var first interface{}
+	delivered := false
+	for i, target := range s.targets {
+		result, err := target.Do(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		_, dropped := result.(*middleware.DroppedResponse)
+		if i == 0 || (!delivered && !dropped) {
+			first = result
+		}
+		if !dropped {
+			delivered = true
+		}
+	}
+	return first, nil
+}
+
+func writeResult(w http.ResponseWriter, msg *message.Message, result interface{}) {
+	switch val := result.(type) {
+	case *kubemq.QueryResponse:
+		setTagHeaders(w, val.Tags)
+		if !val.Executed {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("%s", val.Error))
+			return
+		}
+		if val.Metadata != "" {
+			w.Header().Set(headerMetadata, val.Metadata)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(val.Body)
+	case *kubemq.CommandResponse:
+		setTagHeaders(w, val.Tags)
+		if !val.Executed {
+			writeError(w, http.StatusInternalServerError, fmt.Errorf("%s", val.Error))
+			return
+		}
+		writeJson(w, http.StatusOK, map[string]string{"id": msg.ID})
+	case *middleware.DroppedResponse:
+		writeError(w, http.StatusUnprocessableEntity, val.Err())
+	default:
+		writeJson(w, http.StatusOK, map[string]string{"id": msg.ID})
+	}
+}
+
+func setTagHeaders(w http.ResponseWriter, tags map[string]string) {
+	for key, value := range tags {
+		w.Header().Set(headerTagPrefix+key, value)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	data, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
+
+func (s *Source) Stop() error {
+	if s.server == nil {
+		if s.listener != nil {
+			return s.listener.Close()
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,309 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []*message.Message
+}
+
+func (r *recorder) target(result interface{}, err error) middleware.Middleware {
+	return middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		r.mu.Lock()
+		r.messages = append(r.messages, request.(*message.Message))
+		r.mu.Unlock()
+		return result, err
+	})
+}
+
+func (r *recorder) list() []*message.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*message.Message{}, r.messages...)
+}
+
+func setupSource(t *testing.T, connection config.Metadata, properties config.Metadata, targets ...middleware.Middleware) string {
+	if connection == nil {
+		connection = config.Metadata{}
+	}
+	connection["address"] = "127.0.0.1:0"
+	s := New()
+	require.NoError(t, s.Init(context.Background(), connection, properties, nil))
+	require.NoError(t, s.Start(context.Background(), targets))
+	t.Cleanup(func() {
+		_ = s.Stop()
+	})
+	return "http://" + s.listener.Addr().String()
+}
+
+func doRequest(t *testing.T, req *http.Request) (*http.Response, string) {
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestSource_Delivered(t *testing.T) {
+	r := &recorder{}
+	url := setupSource(t, config.Metadata{"channel": "webhooks", "path": "/hooks"}, nil, r.target(nil, nil), r.target(nil, nil))
+	req, err := http.NewRequest(http.MethodPost, url+"/hooks?source=github&tag=a&tag=b", bytes.NewBufferString("payload"))
+	require.NoError(t, err)
+	req.Header.Set("X-Request-Id", "request-1")
+	req.Header.Set("X-Kubemq-Metadata", "metadata")
+	req.Header.Set("X-Event-Type", "push")
+	resp, body := doRequest(t, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"id":"request-1"}`, body)
+
+	messages := r.list()
+	require.Len(t, messages, 2)
+	msg := messages[0]
+	require.Equal(t, "request-1", msg.ID)
+	require.Equal(t, "webhooks", msg.Channel)
+	require.Equal(t, "metadata", msg.Metadata)
+	require.Equal(t, []byte("payload"), msg.Body)
+	require.Equal(t, message.SourceHTTP, msg.Source)
+	require.Equal(t, "POST", msg.Tags["http.method"])
+	require.Equal(t, "/hooks", msg.Tags["http.path"])
+	require.Equal(t, "push", msg.Tags["header.x-event-type"])
+	require.Equal(t, "github", msg.Tags["query.source"])
+	require.Equal(t, "a,b", msg.Tags["query.tag"])
+
+	resp, _ = doRequest(t, mustRequest(t, http.MethodPost, url+"/other", ""))
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp, _ = doRequest(t, mustRequest(t, http.MethodGet, url+"/hooks", ""))
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestSource_Responses(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     interface{}
+		err        error
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name: "query executed",
+			result: &kubemq.QueryResponse{
+				Executed: true,
+				Metadata: "response-metadata",
+				Body:     []byte("response"),
+				Tags:     map[string]string{"key": "value"},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "response",
+			wantHeader: map[string]string{"X-Kubemq-Metadata": "response-metadata", "X-Kubemq-Tag-Key": "value"},
+		},
+		{
+			name:       "query not executed",
+			result:     &kubemq.QueryResponse{Executed: false, Error: "query failed"},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"query failed"}`,
+		},
+		{
+			name:       "command executed",
+			result:     &kubemq.CommandResponse{Executed: true},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"id"}`,
+		},
+		{
+			name:       "command not executed",
+			result:     &kubemq.CommandResponse{Executed: false, Error: "command failed"},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":"command failed"}`,
+		},
+		{
+			name:       "target error",
+			err:        fmt.Errorf("target unavailable"),
+			wantStatus: http.StatusBadGateway,
+			wantBody:   `{"error":"target unavailable"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			url := setupSource(t, nil, nil, r.target(tt.result, tt.err))
+			req := mustRequest(t, http.MethodPost, url, "request")
+			req.Header.Set("X-Request-Id", "id")
+			resp, body := doRequest(t, req)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantBody, body)
+			for key, value := range tt.wantHeader {
+				require.Equal(t, value, resp.Header.Get(key))
+			}
+		})
+	}
+}
+
+func TestSource_Dropped(t *testing.T) {
+	sampling, err := middleware.NewSamplingMiddleware(config.Metadata{"sample_percent": "0"})
+	require.NoError(t, err)
+	dropped, delivered := &recorder{}, &recorder{}
+	dropping := middleware.Chain(dropped.target(nil, nil), middleware.Sampling(sampling))
+
+	url := setupSource(t, nil, nil, dropping)
+	req := mustRequest(t, http.MethodPost, url, "request")
+	req.Header.Set("X-Request-Id", "id")
+	resp, body := doRequest(t, req)
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	require.Equal(t, `{"error":"request dropped, sampled out"}`, body)
+	require.Empty(t, dropped.list())
+
+	url = setupSource(t, nil, nil, dropping, delivered.target(nil, nil))
+	req = mustRequest(t, http.MethodPost, url, "request")
+	req.Header.Set("X-Request-Id", "id")
+	resp, body = doRequest(t, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `{"id":"id"}`, body)
+	require.Len(t, delivered.list(), 1)
+}
+
+func TestSource_Timeout(t *testing.T) {
+	target := middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	url := setupSource(t, config.Metadata{"timeout_seconds": "1"}, nil, target)
+	start := time.Now()
+	resp, _ := doRequest(t, mustRequest(t, http.MethodPost, url, ""))
+	require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	require.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestSource_LoadBalancing(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	url := setupSource(t, nil, config.Metadata{"load-balancing": "true"}, first.target(nil, nil), second.target(nil, nil))
+	for i := 0; i < 4; i++ {
+		resp, _ := doRequest(t, mustRequest(t, http.MethodPost, url, ""))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	require.Len(t, first.list(), 2)
+	require.Len(t, second.list(), 2)
+}
+
+func TestSource_Auth(t *testing.T) {
+	r := &recorder{}
+	basicUrl := setupSource(t, config.Metadata{"auth_type": "basic", "username": "user", "password": "secret"}, nil, r.target(nil, nil))
+	bearerUrl := setupSource(t, config.Metadata{"auth_type": "bearer", "bearer_token": "token"}, nil, r.target(nil, nil))
+
+	req := mustRequest(t, http.MethodPost, basicUrl, "")
+	resp, _ := doRequest(t, req)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+	req = mustRequest(t, http.MethodPost, basicUrl, "")
+	req.SetBasicAuth("user", "wrong")
+	resp, _ = doRequest(t, req)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req = mustRequest(t, http.MethodPost, basicUrl, "")
+	req.SetBasicAuth("user", "secret")
+	resp, _ = doRequest(t, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = mustRequest(t, http.MethodPost, bearerUrl, "")
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, _ = doRequest(t, req)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	req = mustRequest(t, http.MethodPost, bearerUrl, "")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Proxy-Authorization", "Basic cHJveHk6c2VjcmV0")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("X-Source", "webhook")
+	resp, _ = doRequest(t, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, r.list(), 2)
+	for _, msg := range r.list() {
+		require.NotContains(t, msg.Tags, "header.authorization")
+		require.NotContains(t, msg.Tags, "header.proxy-authorization")
+		require.NotContains(t, msg.Tags, "header.cookie")
+	}
+	require.Equal(t, "webhook", r.list()[1].Tags["header.x-source"])
+}
+
+func TestSource_MaxBodySize(t *testing.T) {
+	r := &recorder{}
+	url := setupSource(t, config.Metadata{"max_body_size": "10"}, nil, r.target(nil, nil))
+	resp, _ := doRequest(t, mustRequest(t, http.MethodPost, url, strings.Repeat("a", 11)))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	resp, _ = doRequest(t, mustRequest(t, http.MethodPost, url, strings.Repeat("a", 10)))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, r.list(), 1)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			cfg:  config.Metadata{},
+		},
+		{
+			name:    "bad address",
+			cfg:     config.Metadata{"address": "localhost"},
+			wantErr: true,
+		},
+		{
+			name:    "bad path",
+			cfg:     config.Metadata{"path": "hooks"},
+			wantErr: true,
+		},
+		{
+			name:    "bad auth type",
+			cfg:     config.Metadata{"auth_type": "digest"},
+			wantErr: true,
+		},
+		{
+			name:    "basic without username",
+			cfg:     config.Metadata{"auth_type": "basic"},
+			wantErr: true,
+		},
+		{
+			name:    "bearer without token",
+			cfg:     config.Metadata{"auth_type": "bearer"},
+			wantErr: true,
+		},
+		{
+			name:    "cert without key",
+			cfg:     config.Metadata{"tls_cert_file": "cert.pem"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func mustRequest(t *testing.T, method, url, body string) *http.Request {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	return req
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
 
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 
@@ -18,6 +21,8 @@ type Source struct {
 	log        *logger.Logger
 	targets    []middleware.Middleware
 	properties config.Metadata
+	gather     *middleware.ScatterGatherMiddleware
+	cancel     context.CancelFunc
 }
 
 func New() *Source {
@@ -34,21 +39,14 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 		return err
 	}
 	s.properties = properties
+	s.gather, err = middleware.NewScatterGatherMiddleware(properties)
+	if err != nil {
+		return err
+	}
 	for i := 0; i < s.opts.sources; i++ {
-		clientId := s.opts.clientId
-		if s.opts.sources > 1 {
-			clientId = fmt.Sprintf("%s-%d", clientId, i)
-		}
-		client, err := kubemq.NewClient(ctx,
-			kubemq.WithAddress(s.opts.host, s.opts.port),
-			kubemq.WithClientId(clientId),
-			kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-			kubemq.WithCheckConnection(true),
-			kubemq.WithAuthToken(s.opts.authToken),
-			kubemq.WithMaxReconnects(s.opts.maxReconnects),
-			kubemq.WithAutoReconnect(s.opts.autoReconnect),
-			kubemq.WithReconnectInterval(s.opts.reconnectIntervalSeconds))
+		client, err := connpool.Client(ctx, s.opts.connection(i))
 		if err != nil {
+			s.release()
 			return err
 		}
 		s.clients = append(s.clients, client)
@@ -56,8 +54,15 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 	return nil
 }
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
-
+	ctx = provenance.WithSource(ctx, fmt.Sprintf("%s:%d", s.opts.host, s.opts.port))
+	ctx, s.cancel = context.WithCancel(ctx)
 	s.targets = target
+	if s.gather.IsActive() {
+		if err := s.gather.Validate(len(target)); err != nil {
+			return err
+		}
+		target = []middleware.Middleware{middleware.ScatterGather(s.gather, target)}
+	}
 	if s.opts.sources > 1 && s.opts.group == "" {
 		s.opts.group = uuid.New().String()
 	}
@@ -118,7 +123,11 @@ func (s *Source) run(ctx context.Context, queryCh <-chan *kubemq.QueryReceive, e
 	}
 }
 func (s *Source) processQuery(ctx context.Context, query *kubemq.QueryReceive, target middleware.Middleware, client *kubemq.Client) (*kubemq.Response, error) {
-	result, err := target.Do(ctx, query)
+	msg, err := message.New(message.SourceQuery, query)
+	if err != nil {
+		return nil, err
+	}
+	result, err := target.Do(ctx, msg)
 	if err != nil {
 		return nil, err
 	}
@@ -127,17 +136,27 @@ func (s *Source) processQuery(ctx context.Context, query *kubemq.QueryReceive, t
 		return s.parseCommandResponse(val, client), nil
 	case *kubemq.QueryResponse:
 		return s.parseQueryResponse(val, client), nil
+	case *middleware.DroppedResponse:
+		return client.NewResponse().SetError(val.Err()), nil
 	default:
 		return client.NewResponse(), nil
 	}
 }
 func (s *Source) Stop() error {
-	for _, client := range s.clients {
-		_ = client.Close()
+	if s.cancel != nil {
+		s.cancel()
 	}
+	s.release()
 	return nil
 }
 
+func (s *Source) release() {
+	for _, client := range s.clients {
+		_ = connpool.Release(client)
+	}
+	s.clients = nil
+}
+
 func (s *Source) parseCommandResponse(cmd *kubemq.CommandResponse, client *kubemq.Client) *kubemq.Response {
 	resp := client.NewResponse().SetTags(cmd.Tags)
 	if cmd.Executed {
@@ -0,0 +1,254 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/ordering"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+type syncSettler struct {
+	sync.Mutex
+	mockSettler
+}
+
+func (m *syncSettler) Ack() error {
+	m.Lock()
+	defer m.Unlock()
+	return m.mockSettler.Ack()
+}
+
+func (m *syncSettler) NAck() error {
+	m.Lock()
+	defer m.Unlock()
+	return m.mockSettler.NAck()
+}
+
+func (m *syncSettler) ReQueue(channel string) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.mockSettler.ReQueue(channel)
+}
+
+func (m *syncSettler) Result() string {
+	m.Lock()
+	defer m.Unlock()
+	return m.result
+}
+
+// failingTarget fails the messages whose id is in fail.
+func failingTarget(fail map[string]bool, delay time.Duration, running, maxRunning *atomic.Int32) middleware.Middleware {
+	return middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		if running != nil {
+			current := running.Inc()
+			defer running.Dec()
+			for {
+				max := maxRunning.Load()
+				if current <= max || maxRunning.CAS(max, current) {
+					break
+				}
+			}
+		}
+		time.Sleep(delay)
+		msg := request.(*message.Message)
+		if fail[msg.ID] {
+			return nil, fmt.Errorf("target error")
+		}
+		return nil, nil
+	})
+}
+
+func newTestBatch(ids ...string) ([]item, []*syncSettler) {
+	var items []item
+	var settlers []*syncSettler
+	for _, id := range ids {
+		msg := newTestMessage(1, map[string]string{"key": id[:1]})
+		msg.SetId(id)
+		st := &syncSettler{}
+		items = append(items, item{message: msg, settler: st})
+		settlers = append(settlers, st)
+	}
+	return items, settlers
+}
+
+func newTestSource(concurrency int, targets ...middleware.Middleware) *Source {
+	return &Source{
+		opts:       options{channel: "queue", concurrency: concurrency},
+		log:        logger.NewLogger("queue-test"),
+		targets:    targets,
+		roundRobin: roundrobin.NewRoundRobin(len(targets)),
+		failure:    failurePolicy{kind: policyNack},
+	}
+}
+
+func TestSource_ProcessBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		policy      failurePolicy
+		fail        map[string]bool
+		want        []string
+		wantSent    []string
+	}{
+		{
+			name:        "sequential - mixed",
+			concurrency: 1,
+			policy:      failurePolicy{kind: policyNack},
+			fail:        map[string]bool{"a2": true, "a4": true},
+			want:        []string{"ack", "nack", "ack", "nack", "ack"},
+		},
+		{
+			name:        "parallel - mixed",
+			concurrency: 3,
+			policy:      failurePolicy{kind: policyNack},
+			fail:        map[string]bool{"a1": true, "a5": true},
+			want:        []string{"nack", "ack", "ack", "ack", "nack"},
+		},
+		{
+			name:        "parallel - requeue",
+			concurrency: 5,
+			policy:      failurePolicy{kind: policyRequeue, channel: "retry"},
+			fail:        map[string]bool{"a3": true},
+			want:        []string{"ack", "ack", "ack", "ack", "ack"},
+			wantSent:    []string{"a3"},
+		},
+		{
+			name:        "sequential - all failed",
+			concurrency: 1,
+			policy:      failurePolicy{kind: policyAckAndDrop},
+			fail:        map[string]bool{"a1": true, "a2": true, "a3": true, "a4": true, "a5": true},
+			want:        []string{"ack", "ack", "ack", "ack", "ack"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSource(tt.concurrency, failingTarget(tt.fail, 0, nil, nil))
+			s.failure = tt.policy
+			items, settlers := newTestBatch("a1", "a2", "a3", "a4", "a5")
+			client := &mockSender{}
+			require.NoError(t, s.processBatch(context.Background(), client, items))
+			for i, st := range settlers {
+				require.Equal(t, tt.want[i], st.Result(), "message %d", i)
+			}
+			var sent []string
+			for _, msg := range client.sent {
+				sent = append(sent, msg.MessageID)
+			}
+			require.Equal(t, tt.wantSent, sent)
+		})
+	}
+}
+
+func TestSource_ProcessBatchSettleError(t *testing.T) {
+	s := newTestSource(1, failingTarget(map[string]bool{"a1": true}, 0, nil, nil))
+	items, settlers := newTestBatch("a1", "a2", "a3")
+	settlers[0].err = fmt.Errorf("nack error")
+	require.Error(t, s.processBatch(context.Background(), &mockSender{}, items))
+	require.Equal(t, "nack", settlers[0].Result())
+	require.Equal(t, "ack", settlers[1].Result())
+	require.Equal(t, "ack", settlers[2].Result())
+}
+
+func TestSource_ProcessBatchConcurrency(t *testing.T) {
+	running := atomic.NewInt32(0)
+	maxRunning := atomic.NewInt32(0)
+	s := newTestSource(2, failingTarget(nil, 50*time.Millisecond, running, maxRunning))
+	items, settlers := newTestBatch("a1", "a2", "a3", "a4", "a5", "a6")
+	require.NoError(t, s.processBatch(context.Background(), &mockSender{}, items))
+	require.EqualValues(t, 2, maxRunning.Load())
+	for _, st := range settlers {
+		require.Equal(t, "ack", st.Result())
+	}
+}
+
+func TestSource_ProcessOrdered(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s := newTestSource(1, failingTarget(map[string]bool{"a2": true}, 0, nil, nil))
+	s.ordering = ordering.Options{Mode: ordering.ModeKey, Key: "tag:key", Partitions: 4}
+	s.pool = pool.NewPartitioned("queue", 4, 10)
+	s.pool.Start(ctx)
+	defer s.pool.Stop()
+	items, settlers := newTestBatch("a1", "a2", "a3", "b1", "b2")
+	require.NoError(t, s.processOrdered(ctx, &mockSender{}, items))
+	require.Equal(t, []string{"ack", "nack", "nack", "ack", "ack"}, []string{
+		settlers[0].Result(), settlers[1].Result(), settlers[2].Result(), settlers[3].Result(), settlers[4].Result(),
+	})
+}
+
+func TestSource_ProcessOrderedPoolStopped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := newTestSource(1, middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		if request.(*message.Message).ID == "a1" {
+			close(started)
+			<-release
+		}
+		return nil, nil
+	}))
+	s.ordering = ordering.Options{Mode: ordering.ModeKey, Key: "tag:key", Partitions: 1}
+	s.pool = pool.NewPartitioned("queue", 1, 4)
+	s.pool.Start(ctx)
+	items, settlers := newTestBatch("a1", "a2", "b1")
+	go func() {
+		<-started
+		s.pool.Stop()
+		for settlers[1].Result() == "" || settlers[2].Result() == "" {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+	}()
+	result := make(chan error, 1)
+	go func() {
+		result <- s.processOrdered(ctx, &mockSender{}, items)
+	}()
+	select {
+	case err := <-result:
+		require.NoError(t, err)
+	case <-ctx.Done():
+		require.Fail(t, "ordered batch did not return after the pool stopped")
+	}
+	require.Equal(t, []string{"ack", "nack", "nack"}, []string{
+		settlers[0].Result(), settlers[1].Result(), settlers[2].Result(),
+	})
+}
+
+func TestSource_ProcessOrderedPoolFull(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := newTestSource(1, middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return nil, nil
+	}))
+	s.ordering = ordering.Options{Mode: ordering.ModeKey, Key: "tag:key", Partitions: 1}
+	s.pool = pool.NewPartitioned("queue", 1, 0)
+	s.pool.Start(ctx)
+	items, settlers := newTestBatch("a1", "a2", "b1")
+	go func() {
+		<-started
+		s.pool.Stop()
+		for settlers[1].Result() == "" {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+	}()
+	require.Error(t, s.processOrdered(ctx, &mockSender{}, items))
+	require.Equal(t, []string{"ack", "nack", "nack"}, []string{
+		settlers[0].Result(), settlers[1].Result(), settlers[2].Result(),
+	})
+}
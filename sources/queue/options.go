@@ -3,24 +3,39 @@ package queue
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 )
 
 const (
-	defaultAddress     = "0.0.0.0:50000"
-	defaultWaitTimeout = 5
-	defaultSources     = 1
+	defaultAddress       = "0.0.0.0:50000"
+	defaultWaitTimeout   = 5
+	defaultSources       = 1
+	consumeModePoll      = "poll"
+	consumeModePipelined = "pipelined"
+	consumeModeStream    = "stream"
 )
 
+var consumeModes = map[string]string{
+	"":                   consumeModePoll,
+	consumeModePoll:      consumeModePoll,
+	consumeModePipelined: consumeModePipelined,
+	consumeModeStream:    consumeModeStream,
+}
+
 type options struct {
-	host        string
-	port        int
-	clientId    string
-	authToken   string
-	channel     string
-	sources     int
-	batchSize   int
-	waitTimeout int
+	host             string
+	port             int
+	clientId         string
+	sharedConnection bool
+	authToken        string
+	tls              tlsconfig.Options
+	channel          string
+	sources          int
+	batchSize        int
+	waitTimeout      int
+	concurrency      int
+	consumeMode      string
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -31,8 +46,13 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
 
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 
 	o.channel, err = cfg.MustParseString("channel")
 	if err != nil {
@@ -51,5 +71,26 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing wait timeout value, %w", err)
 	}
+	o.concurrency, err = cfg.ParseIntWithRange("concurrency", 1, 1, 1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing concurrency value, %w", err)
+	}
+	o.consumeMode, err = cfg.ParseStringMap("consume_mode", consumeModes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing consume mode value, %w", err)
+	}
 	return o, nil
 }
+
+// connection returns the options of the instance-th parallel connection of the source.
+func (o options) connection(instance int) connpool.Options {
+	return connpool.Options{
+		Host:      o.host,
+		Port:      o.port,
+		ClientId:  o.clientId,
+		AuthToken: o.authToken,
+		TLS:       o.tls,
+		Exclusive: !o.sharedConnection,
+		Instance:  instance,
+	}
+}
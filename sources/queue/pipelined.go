@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+const (
+	minPollBackoff = 100 * time.Millisecond
+	maxPollBackoff = 10 * time.Second
+)
+
+// runPipelined consumes the queue in pipelined mode. It still polls, but the next poll is sent as
+// soon as the previous one returns and every received message is pushed to a pool of concurrency
+// workers, so the next poll does not wait for the batch to settle.
+func (s *Source) runPipelined(ctx context.Context, client *queues_stream.QueuesStreamClient) {
+	s.dispatch(ctx, client, func(items chan<- item) {
+		s.receive(ctx, client, items)
+	})
+}
+
+// dispatch starts concurrency workers and runs receive, which pushes the messages to the workers
+// until the source is stopped.
+func (s *Source) dispatch(ctx context.Context, client *queues_stream.QueuesStreamClient, receive func(items chan<- item)) {
+	defer func() {
+		_ = connpool.Release(client)
+	}()
+	items := make(chan item, s.opts.batchSize)
+	wg := sync.WaitGroup{}
+	for i := 0; i < s.opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx, client, items)
+		}()
+	}
+	receive(items)
+	close(items)
+	wg.Wait()
+}
+
+func (s *Source) work(ctx context.Context, client sender, items <-chan item) {
+	for it := range items {
+		if err := s.process(ctx, client, it); err != nil {
+			s.log.Error(err.Error())
+		}
+	}
+}
+
+// receive polls the queue until the source is stopped and pushes the received messages to items.
+// Poll errors are retried with an exponential backoff.
+func (s *Source) receive(ctx context.Context, client *queues_stream.QueuesStreamClient, items chan<- item) {
+	backoff := time.Duration(0)
+	for {
+		if s.isStopped {
+			return
+		}
+		pr := queues_stream.NewPollRequest().
+			SetChannel(s.opts.channel).
+			SetMaxItems(s.opts.batchSize).
+			SetWaitTimeout(s.opts.waitTimeout * 1000).
+			SetAutoAck(false).
+			SetOnErrorFunc(s.onError)
+		pollResp, err := client.Poll(ctx, pr)
+		if err != nil {
+			s.log.Error(err.Error())
+			backoff = nextBackoff(backoff)
+			select {
+			case <-time.After(backoff):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		backoff = 0
+		for _, message := range pollResp.Messages {
+			select {
+			case items <- item{message: message, settler: message}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func nextBackoff(current time.Duration) time.Duration {
+	if current < minPollBackoff {
+		return minPollBackoff
+	}
+	current *= 2
+	if current > maxPollBackoff {
+		return maxPollBackoff
+	}
+	return current
+}
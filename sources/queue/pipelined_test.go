@@ -0,0 +1,183 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestSource_PipelinedWork(t *testing.T) {
+	running := atomic.NewInt32(0)
+	maxRunning := atomic.NewInt32(0)
+	fail := map[string]bool{"a2": true, "a5": true}
+	s := newTestSource(3, failingTarget(fail, 20*time.Millisecond, running, maxRunning))
+	batch, settlers := newTestBatch("a1", "a2", "a3", "a4", "a5", "a6")
+	items := make(chan item, len(batch))
+	for _, it := range batch {
+		items <- it
+	}
+	close(items)
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for i := 0; i < s.opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(context.Background(), &mockSender{}, items)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "workers did not complete")
+	}
+	var got []string
+	for _, st := range settlers {
+		got = append(got, st.Result())
+	}
+	require.Equal(t, []string{"ack", "nack", "ack", "ack", "nack", "ack"}, got)
+	require.Equal(t, int32(3), maxRunning.Load())
+}
+
+func TestSource_NextBackoff(t *testing.T) {
+	backoff := time.Duration(0)
+	var got []time.Duration
+	for i := 0; i < 10; i++ {
+		backoff = nextBackoff(backoff)
+		got = append(got, backoff)
+	}
+	require.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		1600 * time.Millisecond,
+		3200 * time.Millisecond,
+		6400 * time.Millisecond,
+		10 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}, got)
+}
+
+func TestOptions_ConsumeMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", mode: "", want: consumeModePoll},
+		{name: "poll", mode: "poll", want: consumeModePoll},
+		{name: "pipelined", mode: "pipelined", want: consumeModePipelined},
+		{name: "stream", mode: "stream", want: consumeModeStream},
+		{name: "bad mode", mode: "push", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Metadata{
+				"address": "localhost:50000",
+				"channel": "some-channel",
+			}
+			if tt.mode != "" {
+				cfg["consume_mode"] = tt.mode
+			}
+			o, err := parseOptions(cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, o.consumeMode)
+		})
+	}
+}
+
+func TestSource_PipelinedOrdering(t *testing.T) {
+	s := New()
+	err := s.Init(context.Background(), config.Metadata{
+		"address":      "localhost:50000",
+		"channel":      "some-channel",
+		"consume_mode": "pipelined",
+	}, config.Metadata{
+		"ordering": "global",
+	}, nil)
+	require.Error(t, err)
+}
+
+// benchmarkConsume sends b.N messages to a new channel and measures the time until the source
+// delivered all of them. It requires a KubeMQ server on localhost:50000.
+func benchmarkConsume(b *testing.B, mode string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	channel := uuid.New().String()
+	client, err := queues_stream.NewQueuesStreamClient(ctx,
+		queues_stream.WithAddress("localhost", 50000),
+		queues_stream.WithClientId(uuid.New().String()))
+	require.NoError(b, err)
+	defer func() {
+		_ = client.Close()
+	}()
+	var messages []*queues_stream.QueueMessage
+	for i := 0; i < b.N; i++ {
+		messages = append(messages, queues_stream.NewQueueMessage().
+			SetChannel(channel).
+			SetBody([]byte(fmt.Sprintf("message-%d", i))))
+	}
+	_, err = client.Send(ctx, messages...)
+	require.NoError(b, err)
+
+	received := atomic.NewInt32(0)
+	done := make(chan struct{})
+	target := middleware.DoFunc(func(ctx context.Context, request interface{}) (interface{}, error) {
+		if received.Inc() == int32(b.N) {
+			close(done)
+		}
+		return nil, nil
+	})
+	s := New()
+	err = s.Init(ctx, config.Metadata{
+		"address":      "localhost:50000",
+		"client_id":    uuid.New().String(),
+		"channel":      channel,
+		"batch_size":   "32",
+		"wait_timeout": "1",
+		"concurrency":  "4",
+		"consume_mode": mode,
+	}, config.Metadata{}, nil)
+	require.NoError(b, err)
+	b.ResetTimer()
+	require.NoError(b, s.Start(ctx, []middleware.Middleware{target}))
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		b.Fatalf("received %d of %d messages", received.Load(), b.N)
+	}
+	b.StopTimer()
+	_ = s.Stop()
+}
+
+func BenchmarkSource_ConsumePoll(b *testing.B) {
+	benchmarkConsume(b, consumeModePoll)
+}
+
+func BenchmarkSource_ConsumePipelined(b *testing.B) {
+	benchmarkConsume(b, consumeModePipelined)
+}
+
+func BenchmarkSource_ConsumeStream(b *testing.B) {
+	benchmarkConsume(b, consumeModeStream)
+}
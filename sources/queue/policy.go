@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"math"
+	"strconv"
+)
+
+const (
+	policyNack             = "nack"
+	policyRequeue          = "requeue"
+	policyRequeueWithDelay = "requeue-with-delay"
+	policyAckAndDrop       = "ack-and-drop"
+
+	defaultFailureDelaySeconds = 5
+	attemptsTag                = "x-kubemq-bridge-attempts"
+)
+
+var failurePolicyMap = map[string]string{
+	"":                     policyNack,
+	policyNack:             policyNack,
+	policyRequeue:          policyRequeue,
+	policyRequeueWithDelay: policyRequeueWithDelay,
+	policyAckAndDrop:       policyAckAndDrop,
+}
+
+// settler settles a received message. It is implemented by queues_stream.QueueMessage.
+type settler interface {
+	Ack() error
+	NAck() error
+	ReQueue(channel string) error
+}
+
+type sender interface {
+	Send(ctx context.Context, messages ...*queues_stream.QueueMessage) (*queues_stream.SendResult, error)
+}
+
+type failurePolicy struct {
+	kind          string
+	channel       string
+	delaySeconds  int
+	maxAttempts   int
+	poisonChannel string
+}
+
+func parseFailurePolicy(properties config.Metadata, sourceChannel string) (failurePolicy, error) {
+	p := failurePolicy{}
+	var err error
+	p.kind, err = properties.ParseStringMap("failure_policy", failurePolicyMap)
+	if err != nil {
+		return failurePolicy{}, fmt.Errorf("error parsing failure policy value, %w", err)
+	}
+	p.channel = properties.ParseString("failure_requeue_channel", sourceChannel)
+	p.delaySeconds, err = properties.ParseIntWithRange("failure_delay_seconds", defaultFailureDelaySeconds, 1, math.MaxInt32)
+	if err != nil {
+		return failurePolicy{}, fmt.Errorf("error parsing failure delay seconds value, %w", err)
+	}
+	p.maxAttempts, err = properties.ParseIntWithRange("failure_max_attempts", 0, 0, math.MaxInt32)
+	if err != nil {
+		return failurePolicy{}, fmt.Errorf("error parsing failure max attempts value, %w", err)
+	}
+	p.poisonChannel = properties.ParseString("failure_poison_channel", "")
+	if p.poisonChannel != "" && p.maxAttempts == 0 {
+		return failurePolicy{}, fmt.Errorf("failure poison channel requires failure max attempts")
+	}
+	return p, nil
+}
+
+// attempts counts the deliveries of the message: the receive count in the current channel plus
+// the attempts recorded when the message was sent again by a requeue policy.
+func attempts(msg *queues_stream.QueueMessage) int {
+	count := 0
+	if msg.Attributes != nil {
+		count = int(msg.Attributes.ReceiveCount)
+	}
+	if val, err := strconv.Atoi(msg.Tags[attemptsTag]); err == nil {
+		count += val
+	}
+	return count
+}
+
+func canRequeue(msg *queues_stream.QueueMessage) bool {
+	if msg.Policy == nil || msg.Attributes == nil {
+		return true
+	}
+	return msg.Policy.MaxReceiveCount < 1024 && msg.Policy.MaxReceiveCount != msg.Attributes.ReceiveCount
+}
+
+// redelivers reports whether a failed message comes back to the source channel to be received again.
+func (p failurePolicy) redelivers(msg *queues_stream.QueueMessage) bool {
+	if p.maxAttempts > 0 && attempts(msg) >= p.maxAttempts {
+		return false
+	}
+	return p.kind == policyNack && canRequeue(msg)
+}
+
+// handle settles a message that failed on all targets.
+func (p failurePolicy) handle(ctx context.Context, msg *queues_stream.QueueMessage, s settler, client sender) error {
+	if p.maxAttempts > 0 && attempts(msg) >= p.maxAttempts {
+		if p.poisonChannel != "" {
+			return s.ReQueue(p.poisonChannel)
+		}
+		return s.Ack()
+	}
+	switch p.kind {
+	case policyRequeue:
+		if err := p.resend(ctx, msg, client, 0); err != nil {
+			return err
+		}
+		return s.Ack()
+	case policyRequeueWithDelay:
+		if err := p.resend(ctx, msg, client, p.delaySeconds); err != nil {
+			return err
+		}
+		return s.Ack()
+	case policyAckAndDrop:
+		return s.Ack()
+	default:
+		if canRequeue(msg) {
+			return s.NAck()
+		}
+		return s.Ack()
+	}
+}
+
+// resend sends a copy of the message to the requeue channel with the attempts recorded in a tag,
+// so a message requeued to its own channel still reaches max attempts.
+func (p failurePolicy) resend(ctx context.Context, msg *queues_stream.QueueMessage, client sender, delaySeconds int) error {
+	tags := map[string]string{}
+	for key, value := range msg.Tags {
+		tags[key] = value
+	}
+	tags[attemptsTag] = strconv.Itoa(attempts(msg))
+	requeued := queues_stream.NewQueueMessage().
+		SetId(msg.MessageID).
+		SetChannel(p.channel).
+		SetMetadata(msg.Metadata).
+		SetBody(msg.Body).
+		SetTags(tags)
+	if delaySeconds > 0 {
+		requeued.SetPolicyDelaySeconds(delaySeconds)
+	}
+	if msg.Policy != nil {
+		requeued.SetPolicyExpirationSeconds(int(msg.Policy.ExpirationSeconds)).
+			SetPolicyMaxReceiveCount(int(msg.Policy.MaxReceiveCount)).
+			SetPolicyMaxReceiveQueue(msg.Policy.MaxReceiveQueue)
+	}
+	result, err := client.Send(ctx, requeued)
+	if err != nil {
+		return fmt.Errorf("error sending requeued message, %w", err)
+	}
+	for _, r := range result.Results {
+		if r.IsError {
+			return fmt.Errorf("error sending requeued message, %s", r.Error)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	pb "github.com/kubemq-io/protobuf/go"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSettler struct {
+	result  string
+	channel string
+	err     error
+}
+
+func (m *mockSettler) Ack() error {
+	m.result = "ack"
+	return m.err
+}
+
+func (m *mockSettler) NAck() error {
+	m.result = "nack"
+	return m.err
+}
+
+func (m *mockSettler) ReQueue(channel string) error {
+	m.result = "requeue"
+	m.channel = channel
+	return m.err
+}
+
+type mockSender struct {
+	mu   sync.Mutex
+	sent []*queues_stream.QueueMessage
+	err  error
+}
+
+func (m *mockSender) Send(ctx context.Context, messages ...*queues_stream.QueueMessage) (*queues_stream.SendResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, messages...)
+	return &queues_stream.SendResult{Results: []*pb.SendQueueMessageResult{{}}}, nil
+}
+
+func newTestMessage(receiveCount int32, tags map[string]string) *queues_stream.QueueMessage {
+	msg := queues_stream.NewQueueMessage().
+		SetId("id").
+		SetChannel("queue").
+		SetBody([]byte("data")).
+		SetTags(tags)
+	msg.Attributes = &pb.QueueMessageAttributes{ReceiveCount: receiveCount}
+	return msg
+}
+
+func TestFailurePolicy_Parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties config.Metadata
+		want       failurePolicy
+		wantErr    bool
+	}{
+		{
+			name:       "default",
+			properties: config.Metadata{},
+			want:       failurePolicy{kind: policyNack, channel: "queue", delaySeconds: defaultFailureDelaySeconds},
+		},
+		{
+			name: "requeue with delay and poison",
+			properties: config.Metadata{
+				"failure_policy":          "requeue-with-delay",
+				"failure_requeue_channel": "retry",
+				"failure_delay_seconds":   "10",
+				"failure_max_attempts":    "3",
+				"failure_poison_channel":  "poison",
+			},
+			want: failurePolicy{kind: policyRequeueWithDelay, channel: "retry", delaySeconds: 10, maxAttempts: 3, poisonChannel: "poison"},
+		},
+		{
+			name:       "invalid - policy",
+			properties: config.Metadata{"failure_policy": "bad"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid - delay",
+			properties: config.Metadata{"failure_delay_seconds": "0"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid - poison without max attempts",
+			properties: config.Metadata{"failure_poison_channel": "poison"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFailurePolicy(tt.properties, "queue")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFailurePolicy_Handle(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        failurePolicy
+		msg           *queues_stream.QueueMessage
+		wantResult    string
+		wantChannel   string
+		wantSent      int
+		wantRedeliver bool
+	}{
+		{
+			name:          "nack",
+			policy:        failurePolicy{kind: policyNack},
+			msg:           newTestMessage(1, nil),
+			wantResult:    "nack",
+			wantRedeliver: true,
+		},
+		{
+			name:       "requeue",
+			policy:     failurePolicy{kind: policyRequeue, channel: "retry"},
+			msg:        newTestMessage(1, nil),
+			wantResult: "ack",
+			wantSent:   1,
+		},
+		{
+			name:       "requeue with delay",
+			policy:     failurePolicy{kind: policyRequeueWithDelay, channel: "queue", delaySeconds: 5},
+			msg:        newTestMessage(1, nil),
+			wantResult: "ack",
+			wantSent:   1,
+		},
+		{
+			name:       "ack and drop",
+			policy:     failurePolicy{kind: policyAckAndDrop},
+			msg:        newTestMessage(1, nil),
+			wantResult: "ack",
+		},
+		{
+			name:        "max attempts to poison channel",
+			policy:      failurePolicy{kind: policyNack, maxAttempts: 3, poisonChannel: "poison"},
+			msg:         newTestMessage(1, map[string]string{attemptsTag: "2"}),
+			wantResult:  "requeue",
+			wantChannel: "poison",
+		},
+		{
+			name:       "max attempts without poison channel",
+			policy:     failurePolicy{kind: policyNack, maxAttempts: 3},
+			msg:        newTestMessage(3, nil),
+			wantResult: "ack",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &mockSettler{}
+			client := &mockSender{}
+			require.Equal(t, tt.wantRedeliver, tt.policy.redelivers(tt.msg))
+			require.NoError(t, tt.policy.handle(context.Background(), tt.msg, s, client))
+			require.Equal(t, tt.wantResult, s.result)
+			require.Equal(t, tt.wantChannel, s.channel)
+			require.Len(t, client.sent, tt.wantSent)
+		})
+	}
+}
+
+func TestFailurePolicy_SendDelayed(t *testing.T) {
+	p := failurePolicy{kind: policyRequeueWithDelay, channel: "queue", delaySeconds: 5}
+	msg := newTestMessage(2, map[string]string{attemptsTag: "1", "key": "value"})
+	client := &mockSender{}
+	s := &mockSettler{}
+	require.NoError(t, p.handle(context.Background(), msg, s, client))
+	require.Len(t, client.sent, 1)
+	require.Equal(t, "3", client.sent[0].Tags[attemptsTag])
+	require.Equal(t, "value", client.sent[0].Tags["key"])
+	require.EqualValues(t, 5, client.sent[0].Policy.DelaySeconds)
+	require.Equal(t, "1", msg.Tags[attemptsTag])
+
+	client.err = fmt.Errorf("send error")
+	s = &mockSettler{}
+	require.Error(t, p.handle(context.Background(), msg, s, client))
+	require.Empty(t, s.result)
+}
+
+func TestFailurePolicy_RequeueToSourceChannel(t *testing.T) {
+	p := failurePolicy{kind: policyRequeue, channel: "queue", maxAttempts: 3, poisonChannel: "poison"}
+	msg := newTestMessage(1, map[string]string{"key": "value"})
+	for i := 1; i < 3; i++ {
+		client := &mockSender{}
+		s := &mockSettler{}
+		require.NoError(t, p.handle(context.Background(), msg, s, client))
+		require.Equal(t, "ack", s.result)
+		require.Len(t, client.sent, 1)
+		require.Equal(t, "queue", client.sent[0].Channel)
+		require.Equal(t, fmt.Sprintf("%d", i), client.sent[0].Tags[attemptsTag])
+		require.Zero(t, client.sent[0].Policy.DelaySeconds)
+		// the requeued copy is received again as a new message
+		msg = newTestMessage(1, client.sent[0].Tags)
+	}
+	client := &mockSender{}
+	s := &mockSettler{}
+	require.NoError(t, p.handle(context.Background(), msg, s, client))
+	require.Equal(t, "requeue", s.result)
+	require.Equal(t, "poison", s.channel)
+	require.Empty(t, client.sent)
+}
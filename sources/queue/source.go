@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/ordering"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+	"sync"
 	"time"
 
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
 	"github.com/kubemq-io/kubemq-go/queues_stream"
 )
 
@@ -21,6 +27,9 @@ type Source struct {
 	properties        config.Metadata
 	roundRobin        *roundrobin.RoundRobin
 	loadBalancingMode bool
+	ordering          ordering.Options
+	pool              *pool.Pool
+	failure           failurePolicy
 }
 
 func New() *Source {
@@ -28,20 +37,6 @@ func New() *Source {
 
 }
 
-func (s *Source) getQueuesClient(ctx context.Context, id int) (*queues_stream.QueuesStreamClient, error) {
-	return queues_stream.NewQueuesStreamClient(ctx,
-		queues_stream.WithAddress(s.opts.host, s.opts.port),
-		queues_stream.WithClientId(s.opts.clientId),
-		queues_stream.WithCheckConnection(true),
-		queues_stream.WithAutoReconnect(true),
-		queues_stream.WithAuthToken(s.opts.authToken),
-		queues_stream.WithConnectionNotificationFunc(
-			func(msg string) {
-				s.log.Infof(fmt.Sprintf("connection: %d, %s", id, msg))
-			}),
-	)
-
-}
 func (s *Source) onError(err error) {
 	s.log.Error(err.Error())
 }
@@ -56,11 +51,28 @@ func (s *Source) Init(ctx context.Context, connection config.Metadata, propertie
 	if err != nil {
 		return err
 	}
-
+	s.failure, err = parseFailurePolicy(properties, s.opts.channel)
+	if err != nil {
+		return err
+	}
+	s.ordering, err = ordering.ParseOptions(properties)
+	if err != nil {
+		return err
+	}
+	if s.ordering.Enabled() {
+		if s.opts.sources > 1 {
+			return fmt.Errorf("ordering requires a single source")
+		}
+		if s.opts.consumeMode != consumeModePoll {
+			return fmt.Errorf("ordering requires poll consume mode")
+		}
+		s.pool = pool.NewPartitioned(s.opts.channel, s.ordering.Partitions, s.opts.batchSize)
+	}
 	return nil
 }
 
 func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, fmt.Sprintf("%s:%d", s.opts.host, s.opts.port))
 	s.roundRobin = roundrobin.NewRoundRobin(len(target))
 	if s.properties != nil {
 		mode, ok := s.properties["load-balancing"]
@@ -69,19 +81,29 @@ func (s *Source) Start(ctx context.Context, target []middleware.Middleware) erro
 		}
 	}
 	s.targets = target
+	if s.pool != nil {
+		s.pool.Start(ctx)
+	}
 	for i := 0; i < s.opts.sources; i++ {
-		client, err := s.getQueuesClient(ctx, i+1)
+		client, err := connpool.QueuesClient(ctx, s.opts.connection(i))
 		if err != nil {
 			return err
 		}
-		go s.run(ctx, client)
+		switch s.opts.consumeMode {
+		case consumeModePipelined:
+			go s.runPipelined(ctx, client)
+		case consumeModeStream:
+			go s.runStream(ctx, client)
+		default:
+			go s.run(ctx, client)
+		}
 	}
 	return nil
 }
 
 func (s *Source) run(ctx context.Context, client *queues_stream.QueuesStreamClient) {
 	defer func() {
-		_ = client.Close()
+		_ = connpool.Release(client)
 	}()
 	for {
 		if s.isStopped {
@@ -114,39 +136,185 @@ func (s *Source) processQueueMessage(ctx context.Context, client *queues_stream.
 	if !pollResp.HasMessages() {
 		return nil
 	}
-	for _, message := range pollResp.Messages {
-		if s.loadBalancingMode {
-			_, err := s.targets[s.roundRobin.Next()].Do(ctx, message)
-			if err != nil {
-				if message.Policy.MaxReceiveCount < 1024 && message.Policy.MaxReceiveCount != message.Attributes.ReceiveCount {
-					return message.NAck()
-				}
-			}
+	items := make([]item, len(pollResp.Messages))
+	for i, msg := range pollResp.Messages {
+		items[i] = item{message: msg, settler: msg}
+	}
+	if s.ordering.Enabled() {
+		return s.processOrdered(ctx, client, items)
+	}
+	return s.processBatch(ctx, client, items)
+}
 
-		} else {
-			wasExecuted := false
-			for _, target := range s.targets {
-				_, err := target.Do(ctx, message)
-				if err == nil {
-					wasExecuted = true
-				}
+// item is a received message with the settler used to ack, nack or requeue it.
+type item struct {
+	message *queues_stream.QueueMessage
+	settler settler
+}
+
+// batchErrors keeps the first error of a batch while the other messages are still settled.
+type batchErrors struct {
+	sync.Mutex
+	err error
+}
+
+func (b *batchErrors) add(err error) {
+	if err == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// processBatch settles every message of the batch independently, processing up to
+// concurrency messages at a time.
+func (s *Source) processBatch(ctx context.Context, client sender, items []item) error {
+	errs := &batchErrors{}
+	if s.opts.concurrency <= 1 {
+		for _, it := range items {
+			errs.add(s.process(ctx, client, it))
+		}
+		return errs.err
+	}
+	sem := make(chan struct{}, s.opts.concurrency)
+	wg := sync.WaitGroup{}
+	for _, it := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(it item) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs.add(s.process(ctx, client, it))
+		}(it)
+	}
+	wg.Wait()
+	return errs.err
+}
+
+func (s *Source) process(ctx context.Context, client sender, it item) error {
+	if !s.deliver(ctx, it) {
+		return s.failure.handle(ctx, it.message, it.settler, client)
+	}
+	return it.settler.Ack()
+}
+
+// processOrdered delivers the batch with messages of different keys in parallel and messages of
+// the same key one after another. Once a message is nacked for redelivery, every later message
+// with the same key in the batch is nacked as well, so it cannot overtake the failed one. When the
+// pool stops, the messages that were not submitted or are still queued are nacked for redelivery.
+func (s *Source) processOrdered(ctx context.Context, client sender, items []item) error {
+	mu := sync.Mutex{}
+	held := map[string]bool{}
+	claimed := make([]bool, len(items))
+	// claim marks a message as settled by a task or by the batch, whichever comes first
+	claim := func(i int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if claimed[i] {
+			return false
+		}
+		claimed[i] = true
+		return true
+	}
+	errs := &batchErrors{}
+	wg := sync.WaitGroup{}
+	submitted := 0
+	for i, it := range items {
+		i, it := i, it
+		key := s.ordering.PartitionKey(it.message)
+		wg.Add(1)
+		err := s.pool.SubmitKey(ctx, key, func() {
+			if !claim(i) {
+				return
 			}
-			if !wasExecuted {
-				if message.Policy.MaxReceiveCount < 1024 && message.Policy.MaxReceiveCount != message.Attributes.ReceiveCount {
-					return message.NAck()
+			defer wg.Done()
+			mu.Lock()
+			isHeld := held[key]
+			mu.Unlock()
+			if isHeld {
+				errs.add(it.settler.NAck())
+				return
+			}
+			if !s.deliver(ctx, it) {
+				if s.failure.redelivers(it.message) {
+					mu.Lock()
+					held[key] = true
+					mu.Unlock()
 				}
-
+				errs.add(s.failure.handle(ctx, it.message, it.settler, client))
+				return
 			}
-		}
-		err = message.Ack()
+			errs.add(it.settler.Ack())
+		})
 		if err != nil {
-			return err
+			wg.Done()
+			errs.add(err)
+			for _, rest := range items[i:] {
+				errs.add(rest.settler.NAck())
+			}
+			break
 		}
+		submitted++
 	}
-	return nil
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return errs.err
+	case <-s.pool.Done():
+	case <-ctx.Done():
+	}
+	// the pool workers exit without running the queued tasks, so nack the messages they hold
+	for i, it := range items[:submitted] {
+		if claim(i) {
+			errs.add(it.settler.NAck())
+			wg.Done()
+		}
+	}
+	<-done
+	return errs.err
+}
+
+func (s *Source) deliver(ctx context.Context, it item) bool {
+	msg, err := message.New(message.SourceQueue, it.message)
+	if err != nil {
+		s.log.Errorf("error parsing queue message, %s", err.Error())
+		return false
+	}
+	msg.Acker = it.settler
+	if s.loadBalancingMode {
+		_, err := s.targets[s.roundRobin.Next()].Do(ctx, msg)
+		return err == nil
+	}
+	wasExecuted := false
+	for _, target := range s.targets {
+		_, err := target.Do(ctx, msg)
+		if err == nil {
+			wasExecuted = true
+		}
+	}
+	return wasExecuted
 }
 
 func (s *Source) Stop() error {
 	s.isStopped = true
+	if s.pool != nil {
+		s.pool.Stop()
+	}
 	return nil
 }
+
+func (s *Source) Pools() []*pool.Pool {
+	if s.pool == nil {
+		return nil
+	}
+	return []*pool.Pool{s.pool}
+}
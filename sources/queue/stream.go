@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+)
+
+// runStream consumes the queue in stream mode. The client subscription keeps a get request
+// outstanding on the downstream stream and pushes each response as soon as it arrives, and the
+// received messages are processed by the same workers as in pipelined mode.
+func (s *Source) runStream(ctx context.Context, client *queues_stream.QueuesStreamClient) {
+	s.dispatch(ctx, client, func(items chan<- item) {
+		sr := queues_stream.NewSubscribeRequest().
+			SetChannels(s.opts.channel).
+			SetMaxItems(s.opts.batchSize).
+			SetWaitTimeout(s.opts.waitTimeout * 1000).
+			SetAutoAck(false).
+			SetOnErrorFunc(s.onError)
+		responses, err := client.Subscribe(ctx, sr)
+		if err != nil {
+			s.log.Errorf("error subscribing to queue, %s", err.Error())
+			return
+		}
+		s.forward(ctx, responses, items)
+	})
+}
+
+// forward pushes the messages of the subscription responses to items until the subscription is
+// closed or ctx is done.
+func (s *Source) forward(ctx context.Context, responses <-chan *queues_stream.SubscribeResponse, items chan<- item) {
+	for {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				return
+			}
+			for _, message := range resp.Messages {
+				select {
+				case items <- item{message: message, settler: message}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
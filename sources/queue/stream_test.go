@@ -0,0 +1,70 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-go/queues_stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_StreamForward(t *testing.T) {
+	s := newTestSource(1)
+	responses := make(chan *queues_stream.SubscribeResponse, 2)
+	first := []*queues_stream.QueueMessage{
+		queues_stream.NewQueueMessage().SetId("a1"),
+		queues_stream.NewQueueMessage().SetId("a2"),
+	}
+	second := []*queues_stream.QueueMessage{
+		queues_stream.NewQueueMessage().SetId("a3"),
+	}
+	responses <- &queues_stream.SubscribeResponse{Messages: first}
+	responses <- &queues_stream.SubscribeResponse{Messages: second}
+	close(responses)
+	items := make(chan item, 3)
+	s.forward(context.Background(), responses, items)
+	close(items)
+	var got []string
+	for it := range items {
+		require.Equal(t, it.message, it.settler)
+		got = append(got, it.message.MessageID)
+	}
+	require.Equal(t, []string{"a1", "a2", "a3"}, got)
+}
+
+func TestSource_StreamForwardCanceled(t *testing.T) {
+	s := newTestSource(1)
+	responses := make(chan *queues_stream.SubscribeResponse, 1)
+	responses <- &queues_stream.SubscribeResponse{Messages: []*queues_stream.QueueMessage{
+		queues_stream.NewQueueMessage().SetId("a1"),
+		queues_stream.NewQueueMessage().SetId("a2"),
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan item, 1)
+	done := make(chan struct{})
+	go func() {
+		s.forward(ctx, responses, items)
+		close(done)
+	}()
+	require.Equal(t, "a1", (<-items).message.MessageID)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "forward did not return")
+	}
+}
+
+func TestSource_StreamOrdering(t *testing.T) {
+	s := New()
+	err := s.Init(context.Background(), config.Metadata{
+		"address":      "localhost:50000",
+		"channel":      "some-channel",
+		"consume_mode": "stream",
+	}, config.Metadata{
+		"ordering": "global",
+	}, nil)
+	require.Error(t, err)
+}
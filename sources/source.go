@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/checkpoint"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/pool"
 	"github.com/kubemq-io/kubemq-bridges/sources/command"
 	"github.com/kubemq-io/kubemq-bridges/sources/events"
 	events_store "github.com/kubemq-io/kubemq-bridges/sources/events-store"
+	"github.com/kubemq-io/kubemq-bridges/sources/file"
+	"github.com/kubemq-io/kubemq-bridges/sources/http"
 	"github.com/kubemq-io/kubemq-bridges/sources/query"
 	"github.com/kubemq-io/kubemq-bridges/sources/queue"
+	"github.com/kubemq-io/kubemq-bridges/sources/timer"
 )
 
 type Source interface {
@@ -19,6 +25,20 @@ type Source interface {
 	Stop() error
 }
 
+type PoolSource interface {
+	Pools() []*pool.Pool
+}
+
+type CheckpointSource interface {
+	Checkpoint() (checkpoint.Checkpoint, bool)
+	Rewind(sequence uint64) error
+	ResetCheckpoint() error
+}
+
+type GeneratorSource interface {
+	Generators() []*generator.Generator
+}
+
 func Init(ctx context.Context, kind string, connection config.Metadata, properties config.Metadata, log *logger.Logger) (Source, error) {
 	switch kind {
 	case "source.command", "kubemq.command":
@@ -51,6 +71,24 @@ func Init(ctx context.Context, kind string, connection config.Metadata, properti
 			return nil, err
 		}
 		return source, nil
+	case "source.http":
+		source := http.New()
+		if err := source.Init(ctx, connection, properties, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "source.file":
+		source := file.New()
+		if err := source.Init(ctx, connection, properties, log); err != nil {
+			return nil, err
+		}
+		return source, nil
+	case "source.timer":
+		source := timer.New()
+		if err := source.Init(ctx, connection, properties, log); err != nil {
+			return nil, err
+		}
+		return source, nil
 	default:
 		return nil, fmt.Errorf("invalid kind %s for source", kind)
 	}
@@ -0,0 +1,55 @@
+package timer
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+)
+
+const (
+	defaultName         = "timer"
+	defaultChannel      = "timer"
+	defaultInterval     = 60
+	defaultBodyTemplate = `{"name":"{name}","run_id":"{run_id}","sequence":{sequence},"timestamp":"{timestamp}"}`
+)
+
+type options struct {
+	name         string
+	channel      string
+	metadata     string
+	bodyTemplate string
+	schedule     generator.Schedule
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.name = cfg.ParseString("name", defaultName)
+	o.channel = cfg.ParseString("channel", defaultChannel)
+	o.metadata = cfg.ParseString("metadata", "")
+	o.bodyTemplate = cfg.ParseString("body_template", defaultBodyTemplate)
+	cron := cfg.ParseString("cron", "")
+	if cron != "" {
+		if _, ok := cfg["interval_seconds"]; ok {
+			return options{}, fmt.Errorf("cron and interval seconds cannot be set together")
+		}
+		loc, err := time.LoadLocation(cfg.ParseString("timezone", "UTC"))
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing timezone value, %w", err)
+		}
+		o.schedule, err = generator.ParseCron(cron, loc)
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing cron value, %w", err)
+		}
+		return o, nil
+	}
+	interval, err := cfg.ParseIntWithRange("interval_seconds", defaultInterval, 1, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing interval seconds value, %w", err)
+	}
+	o.schedule = generator.Every(time.Duration(interval) * time.Second)
+	return o, nil
+}
@@ -0,0 +1,111 @@
+package timer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/provenance"
+	"github.com/kubemq-io/kubemq-bridges/pkg/roundrobin"
+)
+
+// Source generates messages on a schedule, such as heartbeats to a remote cluster. Every message
+// carries the run id of the source and a sequence, so receivers can detect lost messages.
+type Source struct {
+	opts              options
+	log               *logger.Logger
+	targets           []middleware.Middleware
+	properties        config.Metadata
+	roundRobin        *roundrobin.RoundRobin
+	loadBalancingMode bool
+	generator         *generator.Generator
+	cancel            context.CancelFunc
+	done              chan struct{}
+}
+
+func New() *Source {
+	return &Source{}
+}
+
+func (s *Source) Init(ctx context.Context, connection config.Metadata, properties config.Metadata, log *logger.Logger) error {
+	s.log = log
+	if s.log == nil {
+		s.log = logger.NewLogger("timer")
+	}
+	var err error
+	s.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	s.properties = properties
+	s.generator = generator.New(s.opts.name, s.opts.schedule)
+	return nil
+}
+
+func (s *Source) Start(ctx context.Context, target []middleware.Middleware) error {
+	ctx = provenance.WithSource(ctx, s.opts.name)
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.roundRobin = roundrobin.NewRoundRobin(len(target))
+	if s.properties != nil {
+		mode, ok := s.properties["load-balancing"]
+		if ok && mode == "true" {
+			s.loadBalancingMode = true
+		}
+	}
+	s.targets = target
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		s.generator.Run(ctx, s.emit)
+	}()
+	return nil
+}
+
+func (s *Source) emit(ctx context.Context, tick generator.Tick) error {
+	msg, err := message.New(message.SourceTimer, &message.Message{
+		ID:       fmt.Sprintf("%s-%d", tick.RunId, tick.Sequence),
+		Channel:  s.opts.channel,
+		Metadata: build(s.opts.metadata, tick),
+		Body:     []byte(build(s.opts.bodyTemplate, tick)),
+		Tags: map[string]string{
+			generator.TagName:      tick.Name,
+			generator.TagRunId:     tick.RunId,
+			generator.TagSequence:  fmt.Sprintf("%d", tick.Sequence),
+			generator.TagTimestamp: build("{timestamp}", tick),
+		},
+		Timestamp: tick.Time,
+	})
+	if err != nil {
+		return err
+	}
+	targets := s.targets
+	if s.loadBalancingMode {
+		targets = []middleware.Middleware{s.targets[s.roundRobin.Next()]}
+	}
+	var failed error
+	for _, target := range targets {
+		if _, err := target.Do(ctx, msg); err != nil {
+			s.log.Errorf("error received from target on message %d, %s", tick.Sequence, err.Error())
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (s *Source) Generators() []*generator.Generator {
+	return []*generator.Generator{s.generator}
+}
+
+func (s *Source) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	if s.done != nil {
+		<-s.done
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package timer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	messages []*message.Message
+	err      error
+}
+
+func (r *recorder) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, request.(*message.Message))
+	return nil, r.err
+}
+
+func (r *recorder) list() []*message.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*message.Message{}, r.messages...)
+}
+
+func runSource(t *testing.T, connection config.Metadata, properties config.Metadata, targets ...middleware.Middleware) *Source {
+	s := New()
+	require.NoError(t, s.Init(context.Background(), connection, properties, nil))
+	// tests run on a sub second interval
+	s.generator = generator.New(s.opts.name, generator.Every(20*time.Millisecond))
+	require.NoError(t, s.Start(context.Background(), targets))
+	t.Cleanup(func() {
+		_ = s.Stop()
+	})
+	return s
+}
+
+func TestSource_Heartbeat(t *testing.T) {
+	r := &recorder{}
+	s := runSource(t, config.Metadata{"name": "cluster-a", "channel": "heartbeats", "metadata": "{name}/{sequence}"}, nil, r)
+	require.Eventually(t, func() bool {
+		return len(r.list()) >= 3
+	}, time.Second, time.Millisecond)
+	require.NoError(t, s.Stop())
+
+	stats := s.generator.Stats()
+	checker := generator.NewChecker(0, time.Minute)
+	for i, msg := range r.list() {
+		sequence := uint64(i + 1)
+		require.Equal(t, message.SourceTimer, msg.Source)
+		require.Equal(t, "heartbeats", msg.Channel)
+		require.Equal(t, fmt.Sprintf("%s-%d", stats.RunId, sequence), msg.ID)
+		require.Equal(t, fmt.Sprintf("cluster-a/%d", sequence), msg.Metadata)
+		require.Equal(t, "cluster-a", msg.Tags["timer.name"])
+		require.Equal(t, stats.RunId, msg.Tags["timer.run_id"])
+		require.Equal(t, fmt.Sprintf("%d", sequence), msg.Tags["timer.sequence"])
+		require.Equal(t, msg.Timestamp.UTC().Format(time.RFC3339Nano), msg.Tags["timer.timestamp"])
+
+		body := struct {
+			Name      string    `json:"name"`
+			RunId     string    `json:"run_id"`
+			Sequence  uint64    `json:"sequence"`
+			Timestamp time.Time `json:"timestamp"`
+		}{}
+		require.NoError(t, jsoniter.Unmarshal(msg.Body, &body))
+		require.Equal(t, "cluster-a", body.Name)
+		require.Equal(t, stats.RunId, body.RunId)
+		require.Equal(t, sequence, body.Sequence)
+		require.True(t, msg.Timestamp.Equal(body.Timestamp))
+		require.Equal(t, uint64(0), checker.Check(body.RunId, body.Sequence))
+	}
+	require.Equal(t, uint64(len(r.list())), stats.Generated)
+	require.Equal(t, uint64(0), stats.Failed)
+}
+
+func TestSource_Failed(t *testing.T) {
+	ok, failing := &recorder{}, &recorder{err: fmt.Errorf("target error")}
+	s := runSource(t, config.Metadata{"body_template": "{unix}"}, nil, ok, failing)
+	require.Eventually(t, func() bool {
+		return len(failing.list()) >= 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, s.Stop())
+	stats := s.generator.Stats()
+	require.Equal(t, uint64(0), stats.Generated)
+	require.Equal(t, stats.Sequence, stats.Failed)
+	require.Equal(t, fmt.Sprintf("%d", ok.list()[0].Timestamp.Unix()), string(ok.list()[0].Body))
+}
+
+func TestSource_LoadBalancing(t *testing.T) {
+	first, second := &recorder{}, &recorder{}
+	s := runSource(t, config.Metadata{}, config.Metadata{"load-balancing": "true"}, first, second)
+	require.Eventually(t, func() bool {
+		return len(second.list()) >= 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, s.Stop())
+	require.Equal(t, "1", first.list()[0].Tags["timer.sequence"])
+	require.Equal(t, "2", second.list()[0].Tags["timer.sequence"])
+	require.Equal(t, "3", first.list()[1].Tags["timer.sequence"])
+	require.Equal(t, "4", second.list()[1].Tags["timer.sequence"])
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  config.Metadata{},
+		},
+		{
+			name: "interval",
+			cfg:  config.Metadata{"interval_seconds": "10"},
+		},
+		{
+			name: "cron",
+			cfg:  config.Metadata{"cron": "*/5 * * * *", "timezone": "Europe/London"},
+		},
+		{
+			name:    "bad interval",
+			cfg:     config.Metadata{"interval_seconds": "0"},
+			wantErr: true,
+		},
+		{
+			name:    "bad cron",
+			cfg:     config.Metadata{"cron": "* * *"},
+			wantErr: true,
+		},
+		{
+			name:    "bad timezone",
+			cfg:     config.Metadata{"cron": "@hourly", "timezone": "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "cron and interval",
+			cfg:     config.Metadata{"cron": "@hourly", "interval_seconds": "10"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
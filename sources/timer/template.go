@@ -0,0 +1,32 @@
+package timer
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/generator"
+)
+
+var templateField = regexp.MustCompile(`\{(name|run_id|sequence|timestamp|unix)\}`)
+
+// build replaces the {name}, {run_id}, {sequence}, {timestamp} and {unix} fields of a template
+// with the tick values. Any other text, including other braces, is kept as is, so json bodies can
+// be used as templates.
+func build(template string, tick generator.Tick) string {
+	return templateField.ReplaceAllStringFunc(template, func(match string) string {
+		switch match {
+		case "{name}":
+			return tick.Name
+		case "{run_id}":
+			return tick.RunId
+		case "{sequence}":
+			return fmt.Sprintf("%d", tick.Sequence)
+		case "{timestamp}":
+			return tick.Time.UTC().Format(time.RFC3339Nano)
+		case "{unix}":
+			return fmt.Sprintf("%d", tick.Time.Unix())
+		}
+		return match
+	})
+}
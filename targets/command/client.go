@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
 	"github.com/kubemq-io/kubemq-go"
 	"time"
 )
@@ -30,41 +32,25 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logg
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(c.opts.clientId),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = connpool.Client(ctx, c.opts.connection())
 	if err != nil {
 		return err
 	}
 	return nil
 }
 func (c *Client) Stop() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.client == nil {
+		return nil
 	}
-	return nil
+	return connpool.Release(c.client)
 }
 func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
 
-	var cmd *kubemq.Command
-	switch val := request.(type) {
-	case *kubemq.CommandReceive:
-		cmd = c.parseCommand(val)
-	case *kubemq.Event:
-		cmd = c.parseEvent(val)
-	case *kubemq.EventStoreReceive:
-		cmd = c.parseEventStore(val)
-	case *kubemq.QueryReceive:
-		cmd = c.parseQuery(val)
-	case *kubemq.QueueMessage:
-		cmd = c.parseQueue(val)
-	default:
-		return nil, fmt.Errorf("unknown request type")
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
 	}
+	cmd := c.parse(msg)
 	if c.opts.defaultChannel != "" {
 		cmd.SetChannel(c.opts.defaultChannel)
 	}
@@ -80,45 +66,11 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 
 }
 
-func (c *Client) parseEvent(event *kubemq.Event) *kubemq.Command {
-	return kubemq.NewCommand().
-		SetBody(event.Body).
-		SetMetadata(event.Metadata).
-		SetId(event.Id).
-		SetTags(event.Tags).
-		SetChannel(event.Channel)
-
-}
-func (c *Client) parseEventStore(eventStore *kubemq.EventStoreReceive) *kubemq.Command {
-	return kubemq.NewCommand().
-		SetBody(eventStore.Body).
-		SetMetadata(eventStore.Metadata).
-		SetId(eventStore.Id).
-		SetTags(eventStore.Tags).
-		SetChannel(eventStore.Channel)
-}
-
-func (c *Client) parseQuery(query *kubemq.QueryReceive) *kubemq.Command {
-	return kubemq.NewCommand().
-		SetBody(query.Body).
-		SetMetadata(query.Metadata).
-		SetId(query.Id).
-		SetTags(query.Tags).
-		SetChannel(query.Channel)
-}
-func (c *Client) parseCommand(command *kubemq.CommandReceive) *kubemq.Command {
-	return kubemq.NewCommand().
-		SetBody(command.Body).
-		SetMetadata(command.Metadata).
-		SetId(command.Id).
-		SetTags(command.Tags).
-		SetChannel(command.Channel)
-}
-func (c *Client) parseQueue(message *kubemq.QueueMessage) *kubemq.Command {
+func (c *Client) parse(msg *message.Message) *kubemq.Command {
 	return kubemq.NewCommand().
-		SetBody(message.Body).
-		SetMetadata(message.Metadata).
-		SetId(message.MessageID).
-		SetTags(message.Tags).
-		SetChannel(message.Channel)
+		SetBody(msg.Body).
+		SetMetadata(msg.Metadata).
+		SetId(msg.ID).
+		SetTags(msg.Tags).
+		SetChannel(msg.Channel)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/fortytw2/leaktest"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message/messagetest"
 	"github.com/kubemq-io/kubemq-go"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -383,3 +384,16 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParseMatrix(t *testing.T) {
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			cmd := New().parse(msg)
+			require.Equal(t, "channel", cmd.Channel)
+			require.Equal(t, "id", cmd.Id)
+			require.Equal(t, "metadata", cmd.Metadata)
+			require.Equal(t, []byte("body"), cmd.Body)
+			require.Equal(t, map[string]string{"key": "value"}, cmd.Tags)
+		})
+	}
+}
@@ -3,7 +3,8 @@ package command
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 	"math"
 )
 
@@ -13,12 +14,14 @@ const (
 )
 
 type options struct {
-	host           string
-	port           int
-	clientId       string
-	authToken      string
-	defaultChannel string
-	timeoutSeconds int
+	host             string
+	port             int
+	clientId         string
+	sharedConnection bool
+	authToken        string
+	tls              tlsconfig.Options
+	defaultChannel   string
+	timeoutSeconds   int
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -29,7 +32,12 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.defaultChannel = cfg.ParseString("default_channel", "")
 	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, math.MaxInt32)
 	if err != nil {
@@ -37,3 +45,14 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	}
 	return o, nil
 }
+
+func (o options) connection() connpool.Options {
+	return connpool.Options{
+		Host:      o.host,
+		Port:      o.port,
+		ClientId:  o.clientId,
+		AuthToken: o.authToken,
+		TLS:       o.tls,
+		Exclusive: !o.sharedConnection,
+	}
+}
@@ -4,22 +4,26 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
+	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 
 	"github.com/kubemq-io/kubemq-go"
 	"time"
 )
 
-const (
-	defaultSendTimeout     = 10 * time.Second
-	defaultStreamReconnect = 1 * time.Second
-)
-
 type Client struct {
-	log    *logger.Logger
-	opts   options
-	client *kubemq.Client
-	sendCh chan *kubemq.EventStore
+	log        *logger.Logger
+	opts       options
+	client     *kubemq.Client
+	sendCh     chan *kubemq.EventStore
+	cancel     context.CancelFunc
+	confirms   *confirmations
+	supervisor *supervisor.Supervisor
+	stream     func(ctx context.Context, eventsCh chan *kubemq.EventStore, resultCh chan *kubemq.EventStoreResult, errCh chan error)
+	ping       func(ctx context.Context) error
 }
 
 func New() *Client {
@@ -37,154 +41,159 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logg
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(c.opts.clientId),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
-
+	c.client, err = connpool.Client(ctx, c.opts.connection())
 	if err != nil {
 		return err
 	}
-	c.sendCh = make(chan *kubemq.EventStore, 1)
-	go c.runStreamProcessing(ctx)
+	c.stream = c.client.StreamEventsStore
+	c.ping = func(ctx context.Context) error {
+		_, err := c.client.Ping(ctx)
+		return err
+	}
+	c.start(ctx)
 	return nil
 }
+
+func (c *Client) start(ctx context.Context) {
+	c.sendCh = make(chan *kubemq.EventStore, c.opts.streamBufferSize)
+	c.confirms = newConfirmations()
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.supervisor = supervisor.New(fmt.Sprintf("events-store %s:%d", c.opts.host, c.opts.port), c.runStream, supervisor.Options{}, c.log)
+	c.supervisor.Start(ctx)
+}
 func (c *Client) Stop() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.cancel != nil {
+		c.cancel()
 	}
-	return nil
+	if c.client == nil {
+		return nil
+	}
+	return connpool.Release(c.client)
 }
 func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
-	var eventsStore []*kubemq.EventStore
-	switch val := request.(type) {
-	case *kubemq.CommandReceive:
-		eventsStore = c.parseCommand(val, c.opts.channels)
-	case *kubemq.Event:
-		eventsStore = c.parseEvent(val, c.opts.channels)
-	case *kubemq.EventStoreReceive:
-		eventsStore = c.parseEventStore(val, c.opts.channels)
-	case *kubemq.QueryReceive:
-		eventsStore = c.parseQuery(val, c.opts.channels)
-	case *kubemq.QueueMessage:
-		eventsStore = c.parseQueue(val, c.opts.channels)
-	default:
-		return nil, fmt.Errorf("unknown request type")
-	}
-	for _, es := range eventsStore {
-		select {
-		case c.sendCh <- es:
-
-		case <-time.After(defaultSendTimeout):
-			return nil, fmt.Errorf("error timeout on sending event store")
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
+	}
+	eventsStore := c.parse(msg, c.opts.channels)
+	timer := time.NewTimer(c.opts.sendTimeout)
+	defer timer.Stop()
+	waiters := make([]chan *kubemq.EventStoreResult, len(eventsStore))
+	defer func() {
+		for i, ch := range waiters {
+			if ch != nil {
+				c.confirms.remove(eventsStore[i].Id, ch)
+			}
+		}
+	}()
+	for i, es := range eventsStore {
+		waiters[i] = c.confirms.add(es.Id)
+		if err := c.send(ctx, es, timer); err != nil {
+			return nil, err
 		}
 	}
-	return nil, nil
-}
-
-func (c *Client) runStreamProcessing(ctx context.Context) {
-	for {
-		errCh := make(chan error, 1)
-
-		go func() {
-			resultCh := make(chan *kubemq.EventStoreResult, 1)
-			c.client.StreamEventsStore(ctx, c.sendCh, resultCh, errCh)
-			for {
-				select {
-				case <-resultCh:
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
+	var results []*kubemq.EventStoreResult
+	for i := range eventsStore {
 		select {
-		case <-errCh:
-			time.Sleep(defaultStreamReconnect)
-			return
+		case result := <-waiters[i]:
+			waiters[i] = nil
+			if result.Err != nil {
+				return nil, result.Err
+			}
+			results = append(results, result)
+		case <-timer.C:
+			return nil, fmt.Errorf("error timeout on waiting for event store confirmation")
 		case <-ctx.Done():
-			goto done
+			return nil, ctx.Err()
 		}
 	}
-done:
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	return results, nil
 }
 
-func (c *Client) parseEvent(event *kubemq.Event, channels []string) []*kubemq.EventStore {
-	var eventsStores []*kubemq.EventStore
-	if len(channels) == 0 {
-		channels = append(channels, event.Channel)
+// send waits for the stream to take the event store. While the stream is down, the event store
+// is buffered up to stream_buffer_size and fails fast when the buffer is full.
+func (c *Client) send(ctx context.Context, es *kubemq.EventStore, timer *time.Timer) error {
+	if !c.supervisor.Connected() {
+		select {
+		case c.sendCh <- es:
+			return nil
+		default:
+			return fmt.Errorf("error sending event store, stream is %s and the send buffer is full", c.supervisor.Stats().State)
+		}
 	}
-	for _, channel := range channels {
-		eventsStores = append(eventsStores, kubemq.NewEventStore().
-			SetChannel(channel).
-			SetBody(event.Body).
-			SetMetadata(event.Metadata).
-			SetId(event.Id).
-			SetTags(event.Tags))
+	select {
+	case c.sendCh <- es:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("error timeout on sending event store")
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return eventsStores
-
 }
-func (c *Client) parseEventStore(eventStore *kubemq.EventStoreReceive, channels []string) []*kubemq.EventStore {
-	var eventsStores []*kubemq.EventStore
-	if len(channels) == 0 {
-		channels = append(channels, eventStore.Channel)
+
+func (c *Client) runStream(ctx context.Context, connected func()) error {
+	if err := c.ping(ctx); err != nil {
+		return err
 	}
-	for _, channel := range channels {
-		eventsStores = append(eventsStores, kubemq.NewEventStore().
-			SetChannel(channel).
-			SetBody(eventStore.Body).
-			SetMetadata(eventStore.Metadata).
-			SetId(eventStore.Id).
-			SetTags(eventStore.Tags))
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resultCh := make(chan *kubemq.EventStoreResult, 1)
+	errCh := make(chan error, 2)
+	go c.stream(streamCtx, c.sendCh, resultCh, errCh)
+	connected()
+	for {
+		select {
+		case result := <-resultCh:
+			if !c.confirms.resolve(result) {
+				c.log.Debugf("event store result %s received without a waiting send", result.Id)
+			}
+		case err := <-errCh:
+			c.fail(fmt.Errorf("error on events store stream, %w", err))
+			return err
+		case <-ctx.Done():
+			return nil
+		}
 	}
-	return eventsStores
 }
 
-func (c *Client) parseQuery(query *kubemq.QueryReceive, channels []string) []*kubemq.EventStore {
-	var eventsStores []*kubemq.EventStore
-	if len(channels) == 0 {
-		channels = append(channels, query.Channel)
-	}
-	for _, channel := range channels {
-		eventsStores = append(eventsStores, kubemq.NewEventStore().
-			SetChannel(channel).
-			SetBody(query.Body).
-			SetMetadata(query.Metadata).
-			SetId(query.Id).
-			SetTags(query.Tags))
+// fail fails the sends waiting for a confirmation when the stream breaks, as their results are
+// lost. The buffered event stores of these sends are dropped so they are not sent after their
+// senders got an error and retried.
+func (c *Client) fail(err error) {
+	c.confirms.failAll(err)
+	for {
+		select {
+		case <-c.sendCh:
+		default:
+			return
+		}
 	}
-	return eventsStores
 }
-func (c *Client) parseCommand(command *kubemq.CommandReceive, channels []string) []*kubemq.EventStore {
-	var eventsStores []*kubemq.EventStore
-	if len(channels) == 0 {
-		channels = append(channels, command.Channel)
-	}
-	for _, channel := range channels {
-		eventsStores = append(eventsStores, kubemq.NewEventStore().
-			SetChannel(channel).
-			SetBody(command.Body).
-			SetMetadata(command.Metadata).
-			SetId(command.Id).
-			SetTags(command.Tags))
-	}
-	return eventsStores
+
+// Streams returns the supervisor of the events store stream.
+func (c *Client) Streams() []*supervisor.Supervisor {
+	return []*supervisor.Supervisor{c.supervisor}
 }
-func (c *Client) parseQueue(message *kubemq.QueueMessage, channels []string) []*kubemq.EventStore {
+
+func (c *Client) parse(msg *message.Message, channels []string) []*kubemq.EventStore {
 	var eventsStores []*kubemq.EventStore
 	if len(channels) == 0 {
-		channels = append(channels, message.Channel)
+		channels = append(channels, msg.Channel)
+	}
+	id := msg.ID
+	if id == "" {
+		id = uuid.New().String()
 	}
 	for _, channel := range channels {
 		eventsStores = append(eventsStores, kubemq.NewEventStore().
 			SetChannel(channel).
-			SetBody(message.Body).
-			SetMetadata(message.Metadata).
-			SetId(message.MessageID).
-			SetTags(message.Tags))
+			SetBody(msg.Body).
+			SetMetadata(msg.Metadata).
+			SetId(id).
+			SetTags(msg.Tags))
 	}
 	return eventsStores
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message/messagetest"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
 	"github.com/kubemq-io/kubemq-go"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"sync"
 	"testing"
 
 	"time"
@@ -294,3 +300,262 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParseMatrix(t *testing.T) {
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			c := New()
+			eventsStore := c.parse(msg, []string{"target-a", "target-b"})
+			require.Len(t, eventsStore, 2)
+			for i, channel := range []string{"target-a", "target-b"} {
+				require.Equal(t, channel, eventsStore[i].Channel)
+				require.Equal(t, "id", eventsStore[i].Id)
+				require.Equal(t, "metadata", eventsStore[i].Metadata)
+				require.Equal(t, []byte("body"), eventsStore[i].Body)
+				require.Equal(t, map[string]string{"key": "value"}, eventsStore[i].Tags)
+			}
+			eventsStore = c.parse(msg, nil)
+			require.Len(t, eventsStore, 1)
+			require.Equal(t, "channel", eventsStore[0].Channel)
+		})
+	}
+}
+
+// fakeStream confirms the events it receives, rejecting the channels in rejected, until failAfter
+// events were sent or the stream is killed. Events sent to silent channels are never confirmed.
+// Pings fail while the server is down.
+type fakeStream struct {
+	rejected  map[string]bool
+	silent    map[string]bool
+	failAfter int
+	kill      chan struct{}
+	down      atomic.Bool
+}
+
+func (f *fakeStream) ping(ctx context.Context) error {
+	if f.down.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (f *fakeStream) run(ctx context.Context, eventsCh chan *kubemq.EventStore, resultCh chan *kubemq.EventStoreResult, errCh chan error) {
+	sent := 0
+	for {
+		select {
+		case <-f.kill:
+			errCh <- fmt.Errorf("stream killed")
+			return
+		case es := <-eventsCh:
+			sent++
+			if f.failAfter > 0 && sent >= f.failAfter {
+				errCh <- fmt.Errorf("stream closed")
+				return
+			}
+			if f.silent[es.Channel] {
+				continue
+			}
+			result := &kubemq.EventStoreResult{Id: es.Id, Sent: true}
+			if f.rejected[es.Channel] {
+				result.Sent = false
+				result.Err = fmt.Errorf("channel %s rejected", es.Channel)
+			}
+			select {
+			case resultCh <- result:
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newStreamClient(ctx context.Context, stream *fakeStream, channels []string) *Client {
+	return newStreamClientWithBuffer(ctx, stream, channels, 10)
+}
+
+func newStreamClientWithBuffer(ctx context.Context, stream *fakeStream, channels []string, bufferSize int) *Client {
+	c := New()
+	c.log = logger.NewLogger("events-store")
+	c.opts = options{channels: channels, sendTimeout: 500 * time.Millisecond, streamBufferSize: bufferSize}
+	c.stream = stream.run
+	c.ping = stream.ping
+	c.start(ctx)
+	return c
+}
+
+func TestClient_Confirm(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   *fakeStream
+		channels []string
+		req      *kubemq.Event
+		wantIds  int
+		wantErr  string
+	}{
+		{
+			name:     "confirmed",
+			stream:   &fakeStream{},
+			channels: []string{"a"},
+			req:      &kubemq.Event{Id: "id", Channel: "source"},
+			wantIds:  1,
+		},
+		{
+			name:     "confirmed - multiple channels",
+			stream:   &fakeStream{},
+			channels: []string{"a", "b"},
+			req:      &kubemq.Event{Id: "id", Channel: "source"},
+			wantIds:  2,
+		},
+		{
+			name:     "confirmed - generated id",
+			stream:   &fakeStream{},
+			channels: []string{"a"},
+			req:      &kubemq.Event{Channel: "source"},
+			wantIds:  1,
+		},
+		{
+			name:     "rejected",
+			stream:   &fakeStream{rejected: map[string]bool{"b": true}},
+			channels: []string{"a", "b"},
+			req:      &kubemq.Event{Id: "id", Channel: "source"},
+			wantErr:  "channel b rejected",
+		},
+		{
+			name:     "timeout",
+			stream:   &fakeStream{silent: map[string]bool{"a": true}},
+			channels: []string{"a"},
+			req:      &kubemq.Event{Id: "id", Channel: "source"},
+			wantErr:  "error timeout on waiting for event store confirmation",
+		},
+		{
+			name:     "stream error",
+			stream:   &fakeStream{silent: map[string]bool{"a": true}, failAfter: 2},
+			channels: []string{"a", "b"},
+			req:      &kubemq.Event{Id: "id", Channel: "source"},
+			wantErr:  "error on events store stream, stream closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := newStreamClient(ctx, tt.stream, tt.channels)
+			msg, err := message.New(message.SourceEvents, tt.req)
+			require.NoError(t, err)
+			resp, err := c.Do(ctx, msg)
+			require.Zero(t, c.confirms.len())
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var results []*kubemq.EventStoreResult
+			switch val := resp.(type) {
+			case *kubemq.EventStoreResult:
+				results = append(results, val)
+			case []*kubemq.EventStoreResult:
+				results = val
+			}
+			require.Len(t, results, tt.wantIds)
+			for _, result := range results {
+				require.True(t, result.Sent)
+				require.NotEmpty(t, result.Id)
+				if tt.req.Id != "" {
+					require.Equal(t, tt.req.Id, result.Id)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmations_SameId(t *testing.T) {
+	c := newConfirmations()
+	first := c.add("id")
+	second := c.add("id")
+	third := c.add("id")
+	c.remove("id", second)
+	require.True(t, c.resolve(&kubemq.EventStoreResult{Id: "id", Sent: true}))
+	require.Len(t, first, 1)
+	require.True(t, c.resolve(&kubemq.EventStoreResult{Id: "id", Sent: true}))
+	require.Len(t, third, 1)
+	require.False(t, c.resolve(&kubemq.EventStoreResult{Id: "id", Sent: true}))
+	require.Zero(t, c.len())
+}
+
+func waitState(t *testing.T, sup *supervisor.Supervisor, state string) {
+	require.Eventually(t, func() bool {
+		return sup.Stats().State == state
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClient_StreamReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeStream{
+		silent: map[string]bool{"silent": true},
+		kill:   make(chan struct{}),
+	}
+	c := newStreamClient(ctx, stream, nil)
+	c.opts.sendTimeout = 5 * time.Second
+	var states []string
+	statesMu := sync.Mutex{}
+	c.Streams()[0].OnState(func(stats supervisor.Stats) {
+		statesMu.Lock()
+		defer statesMu.Unlock()
+		states = append(states, stats.State)
+	})
+	waitState(t, c.supervisor, supervisor.StateConnected)
+	_, err := c.Do(ctx, &kubemq.Event{Id: "first", Channel: "ok"})
+	require.NoError(t, err)
+
+	// kill the stream while a send waits for its confirmation
+	stream.down.Store(true)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := c.Do(ctx, &kubemq.Event{Id: "in-flight", Channel: "silent"})
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool {
+		return c.confirms.len() == 1 && len(c.sendCh) == 0
+	}, time.Second, 10*time.Millisecond)
+	stream.kill <- struct{}{}
+	require.EqualError(t, <-errCh, "error on events store stream, stream killed")
+	waitState(t, c.supervisor, supervisor.StateReconnecting)
+
+	// sends while the stream is down are buffered and confirmed after the reconnect
+	go func() {
+		_, err := c.Do(ctx, &kubemq.Event{Id: "buffered", Channel: "ok"})
+		errCh <- err
+	}()
+	require.Eventually(t, func() bool {
+		return len(c.sendCh) == 1
+	}, time.Second, 10*time.Millisecond)
+	stream.down.Store(false)
+	require.NoError(t, <-errCh)
+	waitState(t, c.supervisor, supervisor.StateConnected)
+	stats := c.supervisor.Stats()
+	require.GreaterOrEqual(t, stats.Reconnects, int64(1))
+	require.NotEmpty(t, stats.LastError)
+
+	cancel()
+	<-c.supervisor.Done()
+	require.Equal(t, supervisor.StateStopped, c.supervisor.Stats().State)
+	statesMu.Lock()
+	defer statesMu.Unlock()
+	require.Contains(t, states, supervisor.StateReconnecting)
+	require.Equal(t, supervisor.StateStopped, states[len(states)-1])
+}
+
+func TestClient_StreamFailFast(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeStream{kill: make(chan struct{})}
+	stream.down.Store(true)
+	c := newStreamClientWithBuffer(ctx, stream, nil, 0)
+	_, err := c.Do(ctx, &kubemq.Event{Id: "id", Channel: "ok"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the send buffer is full")
+	require.Zero(t, c.confirms.len())
+}
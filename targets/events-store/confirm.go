@@ -0,0 +1,85 @@
+package events_store
+
+import (
+	"sync"
+
+	"github.com/kubemq-io/kubemq-go"
+)
+
+// confirmations matches the results of the events store stream to the sends waiting for them by
+// event id. Sends with the same id are confirmed in the order they were sent.
+type confirmations struct {
+	mu      sync.Mutex
+	waiters map[string][]chan *kubemq.EventStoreResult
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{
+		waiters: map[string][]chan *kubemq.EventStoreResult{},
+	}
+}
+
+func (c *confirmations) add(id string) chan *kubemq.EventStoreResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan *kubemq.EventStoreResult, 1)
+	c.waiters[id] = append(c.waiters[id], ch)
+	return ch
+}
+
+func (c *confirmations) remove(id string, ch chan *kubemq.EventStoreResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := c.waiters[id]
+	for i, waiter := range list {
+		if waiter == ch {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(c.waiters, id)
+		return
+	}
+	c.waiters[id] = list
+}
+
+// resolve passes the result to the first send waiting for its id. It returns false when no send
+// is waiting, for example after the send timed out.
+func (c *confirmations) resolve(result *kubemq.EventStoreResult) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := c.waiters[result.Id]
+	if len(list) == 0 {
+		return false
+	}
+	list[0] <- result
+	if len(list) == 1 {
+		delete(c.waiters, result.Id)
+	} else {
+		c.waiters[result.Id] = list[1:]
+	}
+	return true
+}
+
+// failAll fails every waiting send, their results are lost with the stream.
+func (c *confirmations) failAll(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, list := range c.waiters {
+		for _, ch := range list {
+			ch <- &kubemq.EventStoreResult{Id: id, Err: err}
+		}
+	}
+	c.waiters = map[string][]chan *kubemq.EventStoreResult{}
+}
+
+func (c *confirmations) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, list := range c.waiters {
+		count += len(list)
+	}
+	return count
+}
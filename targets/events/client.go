@@ -4,21 +4,27 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
 	"github.com/kubemq-io/kubemq-go"
 	"time"
 )
 
 const (
-	defaultSendTimeout     = 10 * time.Second
-	defaultStreamReconnect = 1 * time.Second
+	defaultSendTimeout = 10 * time.Second
 )
 
 type Client struct {
-	log    *logger.Logger
-	opts   options
-	client *kubemq.Client
-	sendCh chan *kubemq.Event
+	log        *logger.Logger
+	opts       options
+	client     *kubemq.Client
+	sendCh     chan *kubemq.Event
+	cancel     context.CancelFunc
+	supervisor *supervisor.Supervisor
+	stream     func(ctx context.Context, eventsCh chan *kubemq.Event, errCh chan error)
+	ping       func(ctx context.Context) error
 }
 
 func New() *Client {
@@ -36,144 +42,103 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logg
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(c.opts.clientId),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = connpool.Client(ctx, c.opts.connection())
 	if err != nil {
 		return err
 	}
-	c.sendCh = make(chan *kubemq.Event, 1)
-	go c.runStreamProcessing(ctx)
+	c.stream = c.client.StreamEvents
+	c.ping = func(ctx context.Context) error {
+		_, err := c.client.Ping(ctx)
+		return err
+	}
+	c.start(ctx)
 	return nil
 }
+
+func (c *Client) start(ctx context.Context) {
+	c.sendCh = make(chan *kubemq.Event, c.opts.streamBufferSize)
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.supervisor = supervisor.New(fmt.Sprintf("events %s:%d", c.opts.host, c.opts.port), c.runStream, supervisor.Options{}, c.log)
+	c.supervisor.Start(ctx)
+}
 func (c *Client) Stop() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.cancel != nil {
+		c.cancel()
 	}
-	return nil
+	if c.client == nil {
+		return nil
+	}
+	return connpool.Release(c.client)
 }
 func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
-	var events []*kubemq.Event
-	switch val := request.(type) {
-	case *kubemq.CommandReceive:
-		events = c.parseCommand(val, c.opts.channels)
-	case *kubemq.Event:
-		events = c.parseEvent(val, c.opts.channels)
-	case *kubemq.EventStoreReceive:
-		events = c.parseEventStore(val, c.opts.channels)
-	case *kubemq.QueryReceive:
-		events = c.parseQuery(val, c.opts.channels)
-	case *kubemq.QueueMessage:
-		events = c.parseQueue(val, c.opts.channels)
-	default:
-		return nil, fmt.Errorf("unknown request type")
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
 	}
+	events := c.parse(msg, c.opts.channels)
 	for _, event := range events {
-		select {
-		case c.sendCh <- event:
-		case <-time.After(defaultSendTimeout):
-			return nil, fmt.Errorf("error timeout on sending event")
+		if err := c.send(ctx, event); err != nil {
+			return nil, err
 		}
 	}
 	return nil, nil
 }
 
-func (c *Client) runStreamProcessing(ctx context.Context) {
-	for {
-		errCh := make(chan error, 1)
-		go func() {
-			c.client.StreamEvents(ctx, c.sendCh, errCh)
-		}()
+// send waits for the stream to take the event. While the stream is down, the event is buffered
+// up to stream_buffer_size and fails fast when the buffer is full.
+func (c *Client) send(ctx context.Context, event *kubemq.Event) error {
+	if !c.supervisor.Connected() {
 		select {
-		case <-errCh:
-			time.Sleep(defaultStreamReconnect)
-		case <-ctx.Done():
-			goto done
+		case c.sendCh <- event:
+			return nil
+		default:
+			return fmt.Errorf("error sending event, stream is %s and the send buffer is full", c.supervisor.Stats().State)
 		}
 	}
-done:
-}
-
-func (c *Client) parseEvent(event *kubemq.Event, channels []string) []*kubemq.Event {
-	var events []*kubemq.Event
-	if len(channels) == 0 {
-		channels = append(channels, event.Channel)
-	}
-
-	for _, channel := range channels {
-		events = append(events, kubemq.NewEvent().
-			SetChannel(channel).
-			SetBody(event.Body).
-			SetMetadata(event.Metadata).
-			SetId(event.Id).
-			SetTags(event.Tags))
-	}
-	return events
-
-}
-func (c *Client) parseEventStore(eventStore *kubemq.EventStoreReceive, channels []string) []*kubemq.Event {
-	var events []*kubemq.Event
-	if len(channels) == 0 {
-		channels = append(channels, eventStore.Channel)
-	}
-	for _, channel := range channels {
-		events = append(events, kubemq.NewEvent().
-			SetChannel(channel).
-			SetBody(eventStore.Body).
-			SetMetadata(eventStore.Metadata).
-			SetId(eventStore.Id).
-			SetTags(eventStore.Tags))
+	select {
+	case c.sendCh <- event:
+		return nil
+	case <-time.After(defaultSendTimeout):
+		return fmt.Errorf("error timeout on sending event")
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return events
 }
 
-func (c *Client) parseQuery(query *kubemq.QueryReceive, channels []string) []*kubemq.Event {
-	var events []*kubemq.Event
-	if len(channels) == 0 {
-		channels = append(channels, query.Channel)
+func (c *Client) runStream(ctx context.Context, connected func()) error {
+	if err := c.ping(ctx); err != nil {
+		return err
 	}
-	for _, channel := range channels {
-		events = append(events, kubemq.NewEvent().
-			SetChannel(channel).
-			SetBody(query.Body).
-			SetMetadata(query.Metadata).
-			SetId(query.Id).
-			SetTags(query.Tags))
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, 2)
+	go c.stream(streamCtx, c.sendCh, errCh)
+	connected()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return nil
 	}
-	return events
 }
-func (c *Client) parseCommand(command *kubemq.CommandReceive, channels []string) []*kubemq.Event {
-	var events []*kubemq.Event
-	if len(channels) == 0 {
-		channels = append(channels, command.Channel)
-	}
 
-	for _, channel := range channels {
-		events = append(events, kubemq.NewEvent().
-			SetChannel(channel).
-			SetBody(command.Body).
-			SetMetadata(command.Metadata).
-			SetId(command.Id).
-			SetTags(command.Tags))
-	}
-	return events
+// Streams returns the supervisor of the events stream.
+func (c *Client) Streams() []*supervisor.Supervisor {
+	return []*supervisor.Supervisor{c.supervisor}
 }
-func (c *Client) parseQueue(message *kubemq.QueueMessage, channels []string) []*kubemq.Event {
+
+func (c *Client) parse(msg *message.Message, channels []string) []*kubemq.Event {
 	var events []*kubemq.Event
 	if len(channels) == 0 {
-		channels = append(channels, message.Channel)
+		channels = append(channels, msg.Channel)
 	}
 	for _, channel := range channels {
 		events = append(events, kubemq.NewEvent().
 			SetChannel(channel).
-			SetBody(message.Body).
-			SetMetadata(message.Metadata).
-			SetId(message.MessageID).
-			SetTags(message.Tags))
+			SetBody(msg.Body).
+			SetMetadata(msg.Metadata).
+			SetId(msg.ID).
+			SetTags(msg.Tags))
 	}
 	return events
 }
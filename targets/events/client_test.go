@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message/messagetest"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
 	"github.com/kubemq-io/kubemq-go"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"testing"
 
 	"time"
@@ -291,3 +295,137 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParseMatrix(t *testing.T) {
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			c := New()
+			events := c.parse(msg, []string{"target-a", "target-b"})
+			require.Len(t, events, 2)
+			for i, channel := range []string{"target-a", "target-b"} {
+				require.Equal(t, channel, events[i].Channel)
+				require.Equal(t, "id", events[i].Id)
+				require.Equal(t, "metadata", events[i].Metadata)
+				require.Equal(t, []byte("body"), events[i].Body)
+				require.Equal(t, map[string]string{"key": "value"}, events[i].Tags)
+			}
+			events = c.parse(msg, nil)
+			require.Len(t, events, 1)
+			require.Equal(t, "channel", events[0].Channel)
+		})
+	}
+}
+
+func TestClient_DoMatrix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newFakeStream()
+	c := newStreamClient(ctx, stream, 2)
+	waitState(t, c.supervisor, supervisor.StateConnected)
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			resp, err := c.Do(ctx, msg)
+			require.NoError(t, err)
+			require.Nil(t, resp)
+			event := <-stream.received
+			require.Equal(t, "channel", event.Channel)
+			require.Equal(t, "id", event.Id)
+			require.Equal(t, "metadata", event.Metadata)
+			require.Equal(t, []byte("body"), event.Body)
+			require.Equal(t, map[string]string{"key": "value"}, event.Tags)
+		})
+	}
+}
+
+// fakeStream records the events it receives until it is killed. Pings fail while the server is
+// down.
+type fakeStream struct {
+	received chan *kubemq.Event
+	kill     chan struct{}
+	down     atomic.Bool
+}
+
+func newFakeStream() *fakeStream {
+	return &fakeStream{
+		received: make(chan *kubemq.Event, 100),
+		kill:     make(chan struct{}),
+	}
+}
+
+func (f *fakeStream) ping(ctx context.Context) error {
+	if f.down.Load() {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (f *fakeStream) run(ctx context.Context, eventsCh chan *kubemq.Event, errCh chan error) {
+	for {
+		select {
+		case <-f.kill:
+			errCh <- fmt.Errorf("stream killed")
+			return
+		case event := <-eventsCh:
+			f.received <- event
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newStreamClient(ctx context.Context, stream *fakeStream, bufferSize int) *Client {
+	c := New()
+	c.log = logger.NewLogger("events")
+	c.opts = options{streamBufferSize: bufferSize}
+	c.stream = stream.run
+	c.ping = stream.ping
+	c.start(ctx)
+	return c
+}
+
+func waitState(t *testing.T, sup *supervisor.Supervisor, state string) {
+	require.Eventually(t, func() bool {
+		return sup.Stats().State == state
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClient_StreamReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newFakeStream()
+	c := newStreamClient(ctx, stream, 2)
+	waitState(t, c.supervisor, supervisor.StateConnected)
+	_, err := c.Do(ctx, &kubemq.Event{Id: "first", Channel: "channel"})
+	require.NoError(t, err)
+	require.Equal(t, "first", (<-stream.received).Id)
+
+	stream.down.Store(true)
+	stream.kill <- struct{}{}
+	waitState(t, c.supervisor, supervisor.StateReconnecting)
+
+	// sends while the stream is down are buffered up to the buffer size and then fail fast
+	for _, id := range []string{"buffered-1", "buffered-2"} {
+		_, err = c.Do(ctx, &kubemq.Event{Id: id, Channel: "channel"})
+		require.NoError(t, err)
+	}
+	_, err = c.Do(ctx, &kubemq.Event{Id: "dropped", Channel: "channel"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the send buffer is full")
+
+	stream.down.Store(false)
+	waitState(t, c.supervisor, supervisor.StateConnected)
+	for _, id := range []string{"buffered-1", "buffered-2"} {
+		select {
+		case event := <-stream.received:
+			require.Equal(t, id, event.Id)
+		case <-time.After(time.Second):
+			require.Fail(t, "buffered event not sent", id)
+		}
+	}
+	stats := c.Streams()[0].Stats()
+	require.GreaterOrEqual(t, stats.Reconnects, int64(1))
+	require.NotEmpty(t, stats.LastError)
+	require.NoError(t, c.Stop())
+	<-c.supervisor.Done()
+	require.Equal(t, supervisor.StateStopped, c.supervisor.Stats().State)
+}
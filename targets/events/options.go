@@ -3,19 +3,24 @@ package events
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 )
 
 const (
-	defaultHost = "localhost:50000"
+	defaultHost             = "localhost:50000"
+	defaultStreamBufferSize = 100
 )
 
 type options struct {
-	host      string
-	port      int
-	clientId  string
-	authToken string
-	channels  []string
+	host             string
+	port             int
+	clientId         string
+	sharedConnection bool
+	authToken        string
+	tls              tlsconfig.Options
+	channels         []string
+	streamBufferSize int
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -26,7 +31,27 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.channels = cfg.ParseStringList("channels")
+	o.streamBufferSize, err = cfg.ParseIntWithRange("stream_buffer_size", defaultStreamBufferSize, 0, 1000000)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing stream buffer size value, %w", err)
+	}
 	return o, nil
 }
+
+func (o options) connection() connpool.Options {
+	return connpool.Options{
+		Host:      o.host,
+		Port:      o.port,
+		ClientId:  o.clientId,
+		AuthToken: o.authToken,
+		TLS:       o.tls,
+		Exclusive: !o.sharedConnection,
+	}
+}
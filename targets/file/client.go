@@ -0,0 +1,55 @@
+package file
+
+import (
+	"context"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/archive"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+type Client struct {
+	log    *logger.Logger
+	opts   options
+	writer *archive.Writer
+}
+
+func New() *Client {
+	return &Client{}
+
+}
+func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("file")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	c.writer, err = archive.NewWriter(c.opts.writer())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.writer.Write(archive.NewRecord(msg)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (c *Client) Stop() error {
+	if c.writer == nil {
+		return nil
+	}
+	return c.writer.Close()
+}
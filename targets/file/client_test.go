@@ -0,0 +1,96 @@
+package file
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/archive"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do(t *testing.T) {
+	dir := t.TempDir()
+	c := New()
+	require.NoError(t, c.Init(context.Background(), config.Metadata{
+		"dir":       dir,
+		"partition": "channel",
+		"gzip":      "true",
+	}, nil))
+	msg, err := message.New(message.SourceEvents, &kubemq.Event{
+		Id:       "id",
+		Channel:  "orders",
+		Metadata: "metadata",
+		Body:     []byte("body"),
+		Tags:     map[string]string{"key": "value"},
+	})
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), msg)
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), "bad request")
+	require.Error(t, err)
+	require.NoError(t, c.Stop())
+
+	files, err := archive.Files(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "orders", "messages-000001.jsonl.gz")}, files)
+	r := archive.NewReader(files)
+	record, err := r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "id", record.ID)
+	require.Equal(t, "orders", record.Channel)
+	require.Equal(t, "metadata", record.Metadata)
+	require.Equal(t, []byte("body"), record.Body)
+	require.Equal(t, "value", record.Tags["key"])
+	require.Equal(t, message.SourceEvents, record.Source)
+	require.True(t, record.Timestamp.Equal(msg.Timestamp))
+	_, err = r.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  config.Metadata{"dir": "archive", "partition": "time", "partition_interval": "day", "max_file_size": "1048576"},
+		},
+		{
+			name:    "no dir",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "bad partition",
+			cfg:     config.Metadata{"dir": "archive", "partition": "size"},
+			wantErr: true,
+		},
+		{
+			name:    "bad partition interval",
+			cfg:     config.Metadata{"dir": "archive", "partition_interval": "week"},
+			wantErr: true,
+		},
+		{
+			name:    "bad max file size",
+			cfg:     config.Metadata{"dir": "archive", "max_file_size": "10"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
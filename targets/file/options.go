@@ -0,0 +1,70 @@
+package file
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/archive"
+)
+
+const (
+	defaultMaxFileSize = 100 * 1024 * 1024
+)
+
+var partitions = map[string]string{
+	"":                       archive.PartitionTime,
+	archive.PartitionTime:    archive.PartitionTime,
+	archive.PartitionChannel: archive.PartitionChannel,
+}
+
+var partitionIntervals = map[string]time.Duration{
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+}
+
+type options struct {
+	dir         string
+	partition   string
+	interval    time.Duration
+	maxFileSize int64
+	gzip        bool
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	o.dir, err = cfg.MustParseString("dir")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing dir value, %w", err)
+	}
+	o.partition, err = cfg.ParseStringMap("partition", partitions)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing partition value, %w", err)
+	}
+	interval := cfg.ParseString("partition_interval", "hour")
+	var ok bool
+	o.interval, ok = partitionIntervals[interval]
+	if !ok {
+		return options{}, fmt.Errorf("error parsing partition interval value, invalid interval %s", interval)
+	}
+	maxFileSize, err := cfg.ParseIntWithRange("max_file_size", defaultMaxFileSize, 1024, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max file size value, %w", err)
+	}
+	o.maxFileSize = int64(maxFileSize)
+	o.gzip = cfg.ParseBool("gzip", false)
+	return o, nil
+}
+
+func (o options) writer() archive.WriterOptions {
+	return archive.WriterOptions{
+		Dir:         o.dir,
+		Partition:   o.partition,
+		Interval:    o.interval,
+		MaxFileSize: o.maxFileSize,
+		Gzip:        o.gzip,
+	}
+}
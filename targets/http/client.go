@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/retry"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
+	"github.com/kubemq-io/kubemq-go"
+)
+
+const (
+	maxIdleConnsPerHost = 100
+	maxErrorBodySize    = 256
+)
+
+type Client struct {
+	log    *logger.Logger
+	opts   options
+	client *http.Client
+}
+
+func New() *Client {
+	return &Client{}
+
+}
+func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logger.Logger) error {
+	c.log = log
+	if c.log == nil {
+		c.log = logger.NewLogger("http")
+	}
+	var err error
+	c.opts, err = parseOptions(connection)
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if c.opts.tls.Enabled {
+		dialer, err := tlsconfig.NewDialer(c.opts.tls, c.log)
+		if err != nil {
+			return err
+		}
+		transport.DialTLSContext = dialer.DialContext
+	}
+	c.client = &http.Client{
+		Transport: transport,
+	}
+	return nil
+}
+
+func (c *Client) Stop() error {
+	if c.client != nil {
+		c.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Do sends the message as an http request. Responses with an error status code are returned as
+// errors; those not in the retry status codes are marked unrecoverable so the retry middleware
+// does not send them again.
+func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.opts.timeout)
+	defer cancel()
+	req, err := c.newRequest(ctx, msg)
+	if err != nil {
+		return nil, retry.Unrecoverable(err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.opts.maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading http response body, %w", err)
+	}
+	if int64(len(body)) > c.opts.maxResponseSize {
+		return nil, retry.Unrecoverable(fmt.Errorf("http response body exceeds %d bytes", c.opts.maxResponseSize))
+	}
+	if c.opts.errorStatusCodes.contains(resp.StatusCode) {
+		err := fmt.Errorf("http request failed with status %s, %s", resp.Status, truncate(body))
+		if !c.opts.retryStatusCodes.contains(resp.StatusCode) {
+			return nil, retry.Unrecoverable(err)
+		}
+		return nil, err
+	}
+	return c.parseResponse(msg, resp, body), nil
+}
+
+func (c *Client) newRequest(ctx context.Context, msg *message.Message) (*http.Request, error) {
+	body := msg.Body
+	if c.opts.bodyTemplate != nil {
+		body = []byte(c.opts.bodyTemplate.build(msg))
+	}
+	method := strings.ToUpper(c.opts.method.build(msg))
+	if method == "" {
+		return nil, fmt.Errorf("http method cannot be empty")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.opts.url.build(msg), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating http request, %w", err)
+	}
+	for key, value := range c.opts.headers {
+		req.Header.Set(key, value.build(msg))
+	}
+	switch c.opts.authType {
+	case authTypeBasic:
+		req.SetBasicAuth(c.opts.username, c.opts.password)
+	case authTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+c.opts.bearerToken)
+	}
+	return req, nil
+}
+
+// parseResponse maps the http response to the response of the source. The status code and the
+// headers are passed as tags, headers prefixed with "header.".
+func (c *Client) parseResponse(msg *message.Message, resp *http.Response, body []byte) interface{} {
+	tags := map[string]string{
+		"http.status_code": strconv.Itoa(resp.StatusCode),
+	}
+	for key, values := range resp.Header {
+		tags["header."+strings.ToLower(key)] = strings.Join(values, ",")
+	}
+	if msg.Source == message.SourceCommand {
+		return &kubemq.CommandResponse{
+			CommandId:  msg.ID,
+			Executed:   true,
+			ExecutedAt: time.Now(),
+			Tags:       tags,
+		}
+	}
+	return &kubemq.QueryResponse{
+		QueryId:    msg.ID,
+		Executed:   true,
+		ExecutedAt: time.Now(),
+		Metadata:   resp.Status,
+		Body:       body,
+		Tags:       tags,
+	}
+}
+
+func truncate(body []byte) string {
+	if len(body) > maxErrorBodySize {
+		return string(body[:maxErrorBodySize]) + "..."
+	}
+	return string(body)
+}
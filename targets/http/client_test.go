@@ -0,0 +1,320 @@
+package http
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/middleware"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+	"github.com/kubemq-io/kubemq-bridges/pkg/retry"
+	"github.com/kubemq-io/kubemq-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+type received struct {
+	method string
+	uri    string
+	header http.Header
+	body   string
+}
+
+func newServer(t *testing.T, status int, handler func(w http.ResponseWriter)) (*httptest.Server, chan received) {
+	requests := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{method: r.Method, uri: r.RequestURI, header: r.Header, body: string(body)}
+		if handler != nil {
+			handler(w)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("response"))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func newClient(t *testing.T, connection config.Metadata) *Client {
+	c := New()
+	require.NoError(t, c.Init(context.Background(), connection, nil))
+	t.Cleanup(func() {
+		_ = c.Stop()
+	})
+	return c
+}
+
+func newMessage(source string) *message.Message {
+	return &message.Message{
+		ID:       "id",
+		Channel:  "orders",
+		Metadata: "created",
+		Body:     []byte(`{"order":1}`),
+		Tags:     map[string]string{"account": "a b/c", "method": "put"},
+		Source:   source,
+	}
+}
+
+func TestClient_Templates(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK, nil)
+	c := newClient(t, config.Metadata{
+		"url":           server.URL + "/api/{channel}/{tag:account}?event={metadata}",
+		"method":        "{tag:method}",
+		"headers":       `{"X-Message-Id":"{id}","Content-Type":"application/json"}`,
+		"body_template": `{"event":"{metadata}","data":{body}}`,
+	})
+	_, err := c.Do(context.Background(), newMessage(message.SourceQueue))
+	require.NoError(t, err)
+	req := <-requests
+	require.Equal(t, http.MethodPut, req.method)
+	require.Equal(t, "/api/orders/a%20b%2Fc?event=created", req.uri)
+	require.Equal(t, "id", req.header.Get("X-Message-Id"))
+	require.Equal(t, "application/json", req.header.Get("Content-Type"))
+	require.Equal(t, `{"event":"created","data":{"order":1}}`, req.body)
+
+	c = newClient(t, config.Metadata{"url": server.URL})
+	_, err = c.Do(context.Background(), newMessage(message.SourceQueue))
+	require.NoError(t, err)
+	req = <-requests
+	require.Equal(t, http.MethodPost, req.method)
+	require.Equal(t, `{"order":1}`, req.body)
+}
+
+func TestClient_Response(t *testing.T) {
+	server, _ := newServer(t, http.StatusCreated, func(w http.ResponseWriter) {
+		w.Header().Set("X-Order-Id", "100")
+	})
+	c := newClient(t, config.Metadata{"url": server.URL})
+
+	result, err := c.Do(context.Background(), newMessage(message.SourceQuery))
+	require.NoError(t, err)
+	query, ok := result.(*kubemq.QueryResponse)
+	require.True(t, ok)
+	require.Equal(t, "id", query.QueryId)
+	require.True(t, query.Executed)
+	require.Equal(t, "201 Created", query.Metadata)
+	require.Equal(t, []byte("response"), query.Body)
+	require.Equal(t, "201", query.Tags["http.status_code"])
+	require.Equal(t, "100", query.Tags["header.x-order-id"])
+
+	result, err = c.Do(context.Background(), newMessage(message.SourceCommand))
+	require.NoError(t, err)
+	command, ok := result.(*kubemq.CommandResponse)
+	require.True(t, ok)
+	require.Equal(t, "id", command.CommandId)
+	require.True(t, command.Executed)
+	require.Equal(t, "201", command.Tags["http.status_code"])
+}
+
+func TestClient_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		connection      config.Metadata
+		wantErr         bool
+		wantRecoverable bool
+		wantCalls       int32
+	}{
+		{
+			name:      "success",
+			status:    http.StatusOK,
+			wantCalls: 1,
+		},
+		{
+			name:            "client error is not retried",
+			status:          http.StatusNotFound,
+			wantErr:         true,
+			wantRecoverable: false,
+			wantCalls:       1,
+		},
+		{
+			name:            "server error is retried",
+			status:          http.StatusServiceUnavailable,
+			wantErr:         true,
+			wantRecoverable: true,
+			wantCalls:       3,
+		},
+		{
+			name:            "too many requests is retried",
+			status:          http.StatusTooManyRequests,
+			wantErr:         true,
+			wantRecoverable: true,
+			wantCalls:       3,
+		},
+		{
+			name:       "custom error status codes",
+			status:     http.StatusNotFound,
+			connection: config.Metadata{"error_status_codes": "500-599"},
+			wantCalls:  1,
+		},
+		{
+			name:            "custom retry status codes",
+			status:          http.StatusConflict,
+			connection:      config.Metadata{"retry_status_codes": "409"},
+			wantErr:         true,
+			wantRecoverable: true,
+			wantCalls:       3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := atomic.NewInt32(0)
+			server, _ := newServer(t, tt.status, func(w http.ResponseWriter) {
+				calls.Inc()
+			})
+			connection := config.Metadata{"url": server.URL}
+			for key, value := range tt.connection {
+				connection[key] = value
+			}
+			c := newClient(t, connection)
+			_, err := c.Do(context.Background(), newMessage(message.SourceQueue))
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Equal(t, tt.wantRecoverable, retry.IsRecoverable(err))
+			} else {
+				require.NoError(t, err)
+			}
+
+			calls.Store(0)
+			retryMiddleware, err := middleware.NewRetryMiddleware(config.Metadata{
+				"retry_attempts":           "3",
+				"retry_delay_milliseconds": "1",
+				"retry_delay_type":         "fixed",
+			}, nil)
+			require.NoError(t, err)
+			md := middleware.Chain(c, middleware.Retry(retryMiddleware))
+			_, err = md.Do(context.Background(), newMessage(message.SourceQueue))
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	server, requests := newServer(t, http.StatusOK, nil)
+	c := newClient(t, config.Metadata{"url": server.URL, "auth_type": "basic", "username": "user", "password": "secret"})
+	_, err := c.Do(context.Background(), newMessage(message.SourceQueue))
+	require.NoError(t, err)
+	req := <-requests
+	r := &http.Request{Header: req.header}
+	username, password, ok := r.BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "secret", password)
+
+	c = newClient(t, config.Metadata{"url": server.URL, "auth_type": "bearer", "bearer_token": "token"})
+	_, err = c.Do(context.Background(), newMessage(message.SourceQueue))
+	require.NoError(t, err)
+	req = <-requests
+	require.Equal(t, "Bearer token", req.header.Get("Authorization"))
+}
+
+func TestClient_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	c := newClient(t, config.Metadata{"url": server.URL})
+	_, err := c.Do(context.Background(), newMessage(message.SourceQuery))
+	require.Error(t, err)
+
+	c = newClient(t, config.Metadata{"url": server.URL, "tls": "true", "tls_ca_file": caFile})
+	result, err := c.Do(context.Background(), newMessage(message.SourceQuery))
+	require.NoError(t, err)
+	require.Equal(t, []byte("secure"), result.(*kubemq.QueryResponse).Body)
+}
+
+func TestClient_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+	c := newClient(t, config.Metadata{"url": server.URL, "timeout_seconds": "1"})
+	start := time.Now()
+	_, err := c.Do(context.Background(), newMessage(message.SourceQueue))
+	require.Error(t, err)
+	require.True(t, retry.IsRecoverable(err))
+	require.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Metadata
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  config.Metadata{"url": "https://service/api/{channel}"},
+		},
+		{
+			name:    "no url",
+			cfg:     config.Metadata{},
+			wantErr: true,
+		},
+		{
+			name:    "bad scheme",
+			cfg:     config.Metadata{"url": "ftp://service"},
+			wantErr: true,
+		},
+		{
+			name:    "bad headers",
+			cfg:     config.Metadata{"url": "http://service", "headers": "Content-Type"},
+			wantErr: true,
+		},
+		{
+			name:    "bad auth type",
+			cfg:     config.Metadata{"url": "http://service", "auth_type": "digest"},
+			wantErr: true,
+		},
+		{
+			name:    "bearer without token",
+			cfg:     config.Metadata{"url": "http://service", "auth_type": "bearer"},
+			wantErr: true,
+		},
+		{
+			name:    "bad status code",
+			cfg:     config.Metadata{"url": "http://service", "error_status_codes": "abc"},
+			wantErr: true,
+		},
+		{
+			name:    "bad status code range",
+			cfg:     config.Metadata{"url": "http://service", "retry_status_codes": "599-500"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	codes, err := parseStatusCodes("404, 500-502")
+	require.NoError(t, err)
+	require.True(t, codes.contains(404))
+	require.True(t, codes.contains(501))
+	require.False(t, codes.contains(405))
+	require.False(t, codes.contains(503))
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
+)
+
+const (
+	defaultMethod           = "POST"
+	defaultTimeout          = 30
+	defaultErrorStatusCodes = "400-599"
+	defaultRetryStatusCodes = "408,429,500-599"
+	defaultMaxResponseSize  = 10 * 1024 * 1024
+	authTypeNone            = "none"
+	authTypeBasic           = "basic"
+	authTypeBearer          = "bearer"
+)
+
+var authTypes = map[string]string{
+	"":             authTypeNone,
+	authTypeNone:   authTypeNone,
+	authTypeBasic:  authTypeBasic,
+	authTypeBearer: authTypeBearer,
+}
+
+type options struct {
+	url              *template
+	method           *template
+	headers          map[string]*template
+	bodyTemplate     *template
+	authType         string
+	username         string
+	password         string
+	bearerToken      string
+	tls              tlsconfig.Options
+	timeout          time.Duration
+	errorStatusCodes statusCodes
+	retryStatusCodes statusCodes
+	maxResponseSize  int64
+}
+
+func parseOptions(cfg config.Metadata) (options, error) {
+	o := options{}
+	var err error
+	rawUrl, err := cfg.MustParseString("url")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing url value, %w", err)
+	}
+	o.url = newTemplate(rawUrl, escapeUrl)
+	parsed, err := url.Parse(o.url.sample())
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing url value, %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return options{}, fmt.Errorf("error parsing url value, scheme must be http or https")
+	}
+	o.method = newTemplate(cfg.ParseString("method", defaultMethod), nil)
+	headers, err := cfg.MustParseJsonMap("headers")
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing headers value, %w", err)
+	}
+	o.headers = map[string]*template{}
+	for key, value := range headers {
+		o.headers[key] = newTemplate(value, nil)
+	}
+	if body := cfg.ParseString("body_template", ""); body != "" {
+		o.bodyTemplate = newTemplate(body, nil)
+	}
+	o.authType, err = cfg.ParseStringMap("auth_type", authTypes)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing auth type value, %w", err)
+	}
+	switch o.authType {
+	case authTypeBasic:
+		o.username, err = cfg.MustParseString("username")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing username value, %w", err)
+		}
+		o.password = cfg.ParseString("password", "")
+	case authTypeBearer:
+		o.bearerToken, err = cfg.MustParseString("bearer_token")
+		if err != nil {
+			return options{}, fmt.Errorf("error parsing bearer token value, %w", err)
+		}
+	}
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	timeout, err := cfg.ParseIntWithRange("timeout_seconds", defaultTimeout, 1, 3600)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
+	}
+	o.timeout = time.Duration(timeout) * time.Second
+	o.errorStatusCodes, err = parseStatusCodes(cfg.ParseString("error_status_codes", defaultErrorStatusCodes))
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing error status codes value, %w", err)
+	}
+	o.retryStatusCodes, err = parseStatusCodes(cfg.ParseString("retry_status_codes", defaultRetryStatusCodes))
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing retry status codes value, %w", err)
+	}
+	maxResponseSize, err := cfg.ParseIntWithRange("max_response_size", defaultMaxResponseSize, 1, 100*1024*1024)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing max response size value, %w", err)
+	}
+	o.maxResponseSize = int64(maxResponseSize)
+	return o, nil
+}
+
+type statusRange struct {
+	from int
+	to   int
+}
+
+// statusCodes is a list of status codes and ranges, such as "404,500-599".
+type statusCodes []statusRange
+
+func parseStatusCodes(value string) (statusCodes, error) {
+	var codes statusCodes
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(item, "-")
+		r := statusRange{}
+		var err error
+		r.from, err = strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %s", item)
+		}
+		r.to = r.from
+		if isRange {
+			r.to, err = strconv.Atoi(strings.TrimSpace(to))
+			if err != nil {
+				return nil, fmt.Errorf("invalid status code range %s", item)
+			}
+		}
+		if r.from < 100 || r.to > 599 || r.from > r.to {
+			return nil, fmt.Errorf("invalid status code range %s", item)
+		}
+		codes = append(codes, r)
+	}
+	return codes, nil
+}
+
+func (s statusCodes) contains(code int) bool {
+	for _, r := range s {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+	return false
+}
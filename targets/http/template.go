@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
+)
+
+var templateField = regexp.MustCompile(`\{(id|channel|metadata|body|tag:[^{}]+)\}`)
+
+// template replaces the {id}, {channel}, {metadata}, {body} and {tag:<name>} fields of a value
+// with the message fields. Any other text, including other braces, is kept as is, so json bodies
+// can be used as templates.
+type template struct {
+	value  string
+	escape func(string) string
+}
+
+func newTemplate(value string, escape func(string) string) *template {
+	return &template{
+		value:  value,
+		escape: escape,
+	}
+}
+
+func (t *template) build(msg *message.Message) string {
+	return templateField.ReplaceAllStringFunc(t.value, func(match string) string {
+		value := msg.Key(match[1 : len(match)-1])
+		if t.escape != nil {
+			return t.escape(value)
+		}
+		return value
+	})
+}
+
+// sample returns the value with every field replaced, for validating the template.
+func (t *template) sample() string {
+	return templateField.ReplaceAllString(t.value, "field")
+}
+
+// escapeUrl escapes a value for both the path and the query of a url.
+func escapeUrl(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kubemq-io/kubemq-go"
+)
+
+var cacheKeyField = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// cacheKey builds the cache key of a query from a template of {channel}, {body_hash} and
+// {tag:<name>} fields.
+type cacheKey struct {
+	template string
+}
+
+func parseCacheKey(template string) (*cacheKey, error) {
+	if template == "" {
+		return nil, fmt.Errorf("template cannot be empty")
+	}
+	for _, match := range cacheKeyField.FindAllStringSubmatch(template, -1) {
+		field := match[1]
+		switch {
+		case field == "channel", field == "body_hash":
+		case strings.HasPrefix(field, "tag:") && len(field) > len("tag:"):
+		default:
+			return nil, fmt.Errorf("invalid cache key field %s", field)
+		}
+	}
+	return &cacheKey{template: template}, nil
+}
+
+func (k *cacheKey) build(query *kubemq.Query) string {
+	return cacheKeyField.ReplaceAllStringFunc(k.template, func(match string) string {
+		field := match[1 : len(match)-1]
+		switch field {
+		case "channel":
+			return query.Channel
+		case "body_hash":
+			sum := sha256.Sum256(query.Body)
+			return hex.EncodeToString(sum[:])
+		default:
+			return query.Tags[strings.TrimPrefix(field, "tag:")]
+		}
+	})
+}
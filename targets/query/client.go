@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message"
 	"github.com/kubemq-io/kubemq-go"
 	"time"
 )
@@ -13,6 +16,8 @@ type Client struct {
 	log    *logger.Logger
 	opts   options
 	client *kubemq.Client
+	cache  *cache.Cache
+	send   func(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error)
 }
 
 func New() *Client {
@@ -29,44 +34,67 @@ func (c *Client) Init(ctx context.Context, connection config.Metadata, log *logg
 	if err != nil {
 		return err
 	}
-	c.client, err = kubemq.NewClient(ctx,
-		kubemq.WithAddress(c.opts.host, c.opts.port),
-		kubemq.WithClientId(c.opts.clientId),
-		kubemq.WithTransportType(kubemq.TransportTypeGRPC),
-		kubemq.WithAuthToken(c.opts.authToken),
-		kubemq.WithCheckConnection(true),
-	)
+	c.client, err = connpool.Client(ctx, c.opts.connection())
 	if err != nil {
 		return err
 	}
+	if c.opts.cacheTTL > 0 {
+		c.cache = cache.New(fmt.Sprintf("%s:%d", c.opts.host, c.opts.port), c.opts.cacheLocalSize, c.opts.cacheTTL)
+	}
+	c.send = c.sendQuery
 	return nil
 }
 func (c *Client) Stop() error {
-	if c.client != nil {
-		return c.client.Close()
+	if c.client == nil {
+		return nil
 	}
-	return nil
+	return connpool.Release(c.client)
 }
 func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, error) {
-	var query *kubemq.Query
-	switch val := request.(type) {
-	case *kubemq.CommandReceive:
-		query = c.parseCommand(val)
-	case *kubemq.Event:
-		query = c.parseEvent(val)
-	case *kubemq.EventStoreReceive:
-		query = c.parseEventStore(val)
-	case *kubemq.QueryReceive:
-		query = c.parseQuery(val)
-	case *kubemq.QueueMessage:
-		query = c.parseQueue(val)
-	default:
-		return nil, fmt.Errorf("unknown request type")
+	msg, err := message.From(request)
+	if err != nil {
+		return nil, err
 	}
+	query := c.parse(msg)
 	if c.opts.defaultChannel != "" {
 		query.SetChannel(c.opts.defaultChannel)
 	}
 	query.SetTimeout(time.Duration(c.opts.timeoutSeconds) * time.Second)
+	queryResponse, err := c.query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return queryResponse, nil
+}
+
+// query sends the query unless the local cache holds its response. With caching enabled, the
+// cache key and ttl are set on the query so the server caches the response as well.
+func (c *Client) query(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
+	if c.cache == nil {
+		return c.send(ctx, query)
+	}
+	key := c.opts.cacheKey.build(query)
+	if val, ok := c.cache.Get(key); ok {
+		c.cache.Hit()
+		queryResponse := *val.(*kubemq.QueryResponse)
+		queryResponse.CacheHit = true
+		return &queryResponse, nil
+	}
+	query.SetCacheKey(key).SetCacheTTL(c.opts.cacheTTL)
+	queryResponse, err := c.send(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if queryResponse.CacheHit {
+		c.cache.Hit()
+	} else {
+		c.cache.Miss()
+	}
+	c.cache.Set(key, queryResponse)
+	return queryResponse, nil
+}
+
+func (c *Client) sendQuery(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
 	queryResponse, err := c.client.SetQuery(query).Send(ctx)
 	if err != nil {
 		return nil, err
@@ -77,45 +105,16 @@ func (c *Client) Do(ctx context.Context, request interface{}) (interface{}, erro
 	return queryResponse, nil
 }
 
-func (c *Client) parseEvent(event *kubemq.Event) *kubemq.Query {
-	return kubemq.NewQuery().
-		SetBody(event.Body).
-		SetMetadata(event.Metadata).
-		SetId(event.Id).
-		SetTags(event.Tags).
-		SetChannel(event.Channel)
-
-}
-func (c *Client) parseEventStore(eventStore *kubemq.EventStoreReceive) *kubemq.Query {
-	return kubemq.NewQuery().
-		SetBody(eventStore.Body).
-		SetMetadata(eventStore.Metadata).
-		SetId(eventStore.Id).
-		SetTags(eventStore.Tags).
-		SetChannel(eventStore.Channel)
+// Cache returns the response cache, or nil when caching is disabled.
+func (c *Client) Cache() *cache.Cache {
+	return c.cache
 }
 
-func (c *Client) parseQuery(query *kubemq.QueryReceive) *kubemq.Query {
-	return kubemq.NewQuery().
-		SetBody(query.Body).
-		SetMetadata(query.Metadata).
-		SetId(query.Id).
-		SetTags(query.Tags).
-		SetChannel(query.Channel)
-}
-func (c *Client) parseCommand(command *kubemq.CommandReceive) *kubemq.Query {
-	return kubemq.NewQuery().
-		SetBody(command.Body).
-		SetMetadata(command.Metadata).
-		SetId(command.Id).
-		SetTags(command.Tags).
-		SetChannel(command.Channel)
-}
-func (c *Client) parseQueue(message *kubemq.QueueMessage) *kubemq.Query {
+func (c *Client) parse(msg *message.Message) *kubemq.Query {
 	return kubemq.NewQuery().
-		SetBody(message.Body).
-		SetMetadata(message.Metadata).
-		SetId(message.MessageID).
-		SetTags(message.Tags).
-		SetChannel(message.Channel)
+		SetBody(msg.Body).
+		SetMetadata(msg.Metadata).
+		SetId(msg.ID).
+		SetTags(msg.Tags).
+		SetChannel(msg.Channel)
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
 
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message/messagetest"
 	"github.com/kubemq-io/kubemq-go"
 
 	"github.com/stretchr/testify/require"
@@ -403,3 +405,120 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParseMatrix(t *testing.T) {
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			query := New().parse(msg)
+			require.Equal(t, "channel", query.Channel)
+			require.Equal(t, "id", query.Id)
+			require.Equal(t, "metadata", query.Metadata)
+			require.Equal(t, []byte("body"), query.Body)
+			require.Equal(t, map[string]string{"key": "value"}, query.Tags)
+		})
+	}
+}
+
+func TestClient_DoMatrix(t *testing.T) {
+	c := New()
+	c.opts = options{timeoutSeconds: 10}
+	var sent *kubemq.Query
+	c.send = func(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
+		sent = query
+		return &kubemq.QueryResponse{QueryId: query.Id, Executed: true, Body: []byte("response")}, nil
+	}
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			resp, err := c.Do(context.Background(), msg)
+			require.NoError(t, err)
+			require.Equal(t, []byte("response"), resp.(*kubemq.QueryResponse).Body)
+			require.Equal(t, "channel", sent.Channel)
+			require.Equal(t, "id", sent.Id)
+			require.Equal(t, "metadata", sent.Metadata)
+			require.Equal(t, []byte("body"), sent.Body)
+			require.Equal(t, map[string]string{"key": "value"}, sent.Tags)
+			require.Equal(t, 10*time.Second, sent.Timeout)
+		})
+	}
+}
+
+func TestClient_CacheKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "default",
+			template: defaultCacheKeyTemplate,
+			want:     "channel:230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5",
+		},
+		{
+			name:     "tags",
+			template: "{channel}/{tag:region}/{tag:missing}",
+			want:     "channel/eu/",
+		},
+		{
+			name:     "invalid field",
+			template: "{channel}:{metadata}",
+			wantErr:  true,
+		},
+		{
+			name:     "empty tag",
+			template: "{tag:}",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := parseCacheKey(tt.template)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			query := kubemq.NewQuery().
+				SetChannel("channel").
+				SetBody([]byte("body")).
+				SetTags(map[string]string{"region": "eu"})
+			require.Equal(t, tt.want, key.build(query))
+		})
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	c := New()
+	var err error
+	c.opts, err = parseOptions(config.Metadata{
+		"address":           "localhost:50000",
+		"cache_ttl_seconds": "60",
+		"cache_local_size":  "10",
+	})
+	require.NoError(t, err)
+	c.cache = cache.New("cache", c.opts.cacheLocalSize, c.opts.cacheTTL)
+	var sent []*kubemq.Query
+	c.send = func(ctx context.Context, query *kubemq.Query) (*kubemq.QueryResponse, error) {
+		sent = append(sent, query)
+		if string(query.Body) == "error" {
+			return nil, fmt.Errorf("query error")
+		}
+		return &kubemq.QueryResponse{Executed: true, Body: query.Body}, nil
+	}
+	for _, body := range []string{"a", "b", "a", "error", "a"} {
+		resp, err := c.Do(context.Background(), kubemq.NewEvent().SetChannel("channel").SetBody([]byte(body)))
+		if body == "error" {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, []byte(body), resp.(*kubemq.QueryResponse).Body)
+	}
+	require.Len(t, sent, 3)
+	require.NotEmpty(t, sent[0].CacheKey)
+	require.Equal(t, 60*time.Second, sent[0].CacheTTL)
+	stats := c.Cache().Stats()
+	require.Equal(t, uint64(2), stats.Hits)
+	require.Equal(t, uint64(2), stats.Misses)
+	require.Equal(t, 2, stats.Size)
+}
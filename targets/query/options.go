@@ -3,22 +3,30 @@ package query
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 	"math"
+	"time"
 )
 
 const (
-	defaultHost           = "localhost:5000"
-	defaultTimeoutSeconds = 600
+	defaultHost             = "localhost:5000"
+	defaultTimeoutSeconds   = 600
+	defaultCacheKeyTemplate = "{channel}:{body_hash}"
 )
 
 type options struct {
-	host           string
-	port           int
-	clientId       string
-	authToken      string
-	defaultChannel string
-	timeoutSeconds int
+	host             string
+	port             int
+	clientId         string
+	sharedConnection bool
+	authToken        string
+	tls              tlsconfig.Options
+	defaultChannel   string
+	timeoutSeconds   int
+	cacheTTL         time.Duration
+	cacheKey         *cacheKey
+	cacheLocalSize   int
 }
 
 func parseOptions(cfg config.Metadata) (options, error) {
@@ -29,11 +37,40 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.defaultChannel = cfg.ParseString("default_channel", "")
 	o.timeoutSeconds, err = cfg.ParseIntWithRange("timeout_seconds", defaultTimeoutSeconds, 1, math.MaxInt32)
 	if err != nil {
 		return options{}, fmt.Errorf("error parsing timeout seconds value, %w", err)
 	}
+	cacheTTLSeconds, err := cfg.ParseIntWithRange("cache_ttl_seconds", 0, 0, math.MaxInt32)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache ttl seconds value, %w", err)
+	}
+	o.cacheTTL = time.Duration(cacheTTLSeconds) * time.Second
+	o.cacheKey, err = parseCacheKey(cfg.ParseString("cache_key_template", defaultCacheKeyTemplate))
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache key template value, %w", err)
+	}
+	o.cacheLocalSize, err = cfg.ParseIntWithRange("cache_local_size", 0, 0, 1000000)
+	if err != nil {
+		return options{}, fmt.Errorf("error parsing cache local size value, %w", err)
+	}
 	return o, nil
 }
+
+func (o options) connection() connpool.Options {
+	return connpool.Options{
+		Host:      o.host,
+		Port:      o.port,
+		ClientId:  o.clientId,
+		AuthToken: o.authToken,
+		TLS:       o.tls,
+		Exclusive: !o.sharedConnection,
+	}
+}
@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/message/messagetest"
 	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
 	"github.com/kubemq-io/kubemq-go"
 
@@ -328,3 +329,24 @@ func TestClient_Init(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ParseMatrix(t *testing.T) {
+	for source, msg := range messagetest.Messages(t) {
+		t.Run(source, func(t *testing.T) {
+			c := New()
+			c.opts.delaySeconds = 5
+			c.opts.deadLetterQueue = "dead-letter"
+			messages := c.parse(msg, []string{"target-a", "target-b"})
+			require.Len(t, messages, 2)
+			for i, channel := range []string{"target-a", "target-b"} {
+				require.Equal(t, channel, messages[i].Channel)
+				require.Equal(t, "id", messages[i].MessageID)
+				require.Equal(t, "metadata", messages[i].Metadata)
+				require.Equal(t, []byte("body"), messages[i].Body)
+				require.Equal(t, map[string]string{"key": "value"}, messages[i].Tags)
+				require.Equal(t, int32(5), messages[i].Policy.DelaySeconds)
+				require.Equal(t, "dead-letter", messages[i].Policy.MaxReceiveQueue)
+			}
+		})
+	}
+}
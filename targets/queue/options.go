@@ -3,7 +3,8 @@ package queue
 import (
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
-	"github.com/kubemq-io/kubemq-bridges/pkg/uuid"
+	"github.com/kubemq-io/kubemq-bridges/pkg/connpool"
+	"github.com/kubemq-io/kubemq-bridges/pkg/tlsconfig"
 	"math"
 )
 
@@ -15,7 +16,9 @@ type options struct {
 	host              string
 	port              int
 	clientId          string
+	sharedConnection  bool
 	authToken         string
+	tls               tlsconfig.Options
 	channels          []string
 	expirationSeconds int
 	delaySeconds      int
@@ -31,7 +34,12 @@ func parseOptions(cfg config.Metadata) (options, error) {
 		return options{}, fmt.Errorf("error parsing address value, %w", err)
 	}
 	o.authToken = cfg.ParseString("auth_token", "")
-	o.clientId = cfg.ParseString("client_id", uuid.New().String())
+	o.tls, err = tlsconfig.ParseOptions(cfg)
+	if err != nil {
+		return options{}, err
+	}
+	o.clientId = cfg.ParseString("client_id", "")
+	o.sharedConnection = cfg.ParseBool("shared_connection", true)
 	o.channels = cfg.ParseStringList("channels")
 	if len(o.channels) == 0 {
 		return options{}, fmt.Errorf("error parsing channles, cannot be empty")
@@ -51,3 +59,14 @@ func parseOptions(cfg config.Metadata) (options, error) {
 	o.deadLetterQueue = cfg.ParseString("dead_letter_queue", "")
 	return o, nil
 }
+
+func (o options) connection() connpool.Options {
+	return connpool.Options{
+		Host:      o.host,
+		Port:      o.port,
+		ClientId:  o.clientId,
+		AuthToken: o.authToken,
+		TLS:       o.tls,
+		Exclusive: !o.sharedConnection,
+	}
+}
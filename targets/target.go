@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/kubemq-io/kubemq-bridges/config"
+	"github.com/kubemq-io/kubemq-bridges/pkg/cache"
 	"github.com/kubemq-io/kubemq-bridges/pkg/logger"
+	"github.com/kubemq-io/kubemq-bridges/pkg/supervisor"
 	"github.com/kubemq-io/kubemq-bridges/targets/command"
 	"github.com/kubemq-io/kubemq-bridges/targets/events"
 	events_store "github.com/kubemq-io/kubemq-bridges/targets/events-store"
+	"github.com/kubemq-io/kubemq-bridges/targets/file"
+	"github.com/kubemq-io/kubemq-bridges/targets/http"
 	"github.com/kubemq-io/kubemq-bridges/targets/query"
 	"github.com/kubemq-io/kubemq-bridges/targets/queue"
 )
@@ -18,6 +22,16 @@ type Target interface {
 	Stop() error
 }
 
+// CacheTarget is implemented by targets caching their responses.
+type CacheTarget interface {
+	Cache() *cache.Cache
+}
+
+// StreamTarget is implemented by targets sending over supervised streams.
+type StreamTarget interface {
+	Streams() []*supervisor.Supervisor
+}
+
 func Init(ctx context.Context, kind string, connection config.Metadata, log *logger.Logger) (Target, error) {
 
 	switch kind {
@@ -51,6 +65,18 @@ func Init(ctx context.Context, kind string, connection config.Metadata, log *log
 			return nil, err
 		}
 		return target, nil
+	case "target.http":
+		target := http.New()
+		if err := target.Init(ctx, connection, log); err != nil {
+			return nil, err
+		}
+		return target, nil
+	case "target.file":
+		target := file.New()
+		if err := target.Init(ctx, connection, log); err != nil {
+			return nil, err
+		}
+		return target, nil
 	default:
 		return nil, fmt.Errorf("invalid kind %s for target", kind)
 	}
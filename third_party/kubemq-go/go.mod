@@ -0,0 +1,16 @@
+module github.com/kubemq-io/kubemq-go
+
+go 1.15
+
+require (
+	github.com/go-resty/resty/v2 v2.7.0
+	github.com/gorilla/websocket v1.5.0
+	github.com/kubemq-io/protobuf v1.3.1
+	github.com/nats-io/nuid v1.0.1
+	github.com/spf13/pflag v1.0.5
+	github.com/spf13/viper v1.10.1
+	github.com/stretchr/testify v1.7.0
+	go.opencensus.io v0.23.0
+	go.uber.org/atomic v1.9.0
+	google.golang.org/grpc v1.43.0
+)